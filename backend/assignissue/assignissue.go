@@ -1,13 +1,13 @@
-package main
+// Package assignissue provides a backend for automated GitHub issue assignment.
+package assignissue
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 
-	"github.com/go-yaml/yaml"
 	"github.com/google/go-github/v28/github"
 
 	"github.com/heupr/heupr/backend"
@@ -47,36 +47,35 @@ func stringPtr(input string) *string {
 	return &input
 }
 
-type webhookEvent struct {
-	Name    string   `yaml:"name"`
-	Actions []string `yaml:"actions"`
-}
-
 type settings struct {
 	Contributors []string `yaml:"contributors"`
 }
 
-type backendObj struct {
-	Name     string         `yaml:"name"`
-	Events   []webhookEvent `yaml:"events"`
-	Settings settings       `yaml:"settings"`
-	Location string         `yaml:"location"`
-}
-
-type configObj struct {
-	Backends []backendObj `yaml:"backends"`
+func init() {
+	backend.Register("assignissue", func() backend.BackendV2 {
+		return &bnkd{}
+	})
 }
 
-// Backend implements the backend package interface
-var Backend bnkd
-
 type bnkd struct {
 	github *github.Client
 	help   helper
 }
 
+// Manifest declares the event types the backend acts on
+func (b *bnkd) Manifest() backend.Manifest {
+	return backend.Manifest{
+		Events: []string{"issues"},
+	}
+}
+
+// Settings returns the type the backend settings block is validated against
+func (b *bnkd) Settings() interface{} {
+	return &settings{}
+}
+
 // Configure configures the backend with a client and helper struct
-func (b *bnkd) Configure(c *github.Client) {
+func (b *bnkd) Configure(ctx context.Context, r backend.Request, c *github.Client) {
 	b.github = c
 	b.help = &help{}
 }
@@ -103,29 +102,24 @@ func parseRepos(event string, payloadBytes []byte) ([]*github.Repository, error)
 }
 
 // Prepare processes existing issues and establishes indexes for target repos
-func (b *bnkd) Prepare(p backend.Payload) error {
-	log.Printf("prepare payload bytes: %s\n", string(p.Bytes()))
+func (b *bnkd) Prepare(ctx context.Context, r backend.Request, p backend.Payload) error {
+	r.Printf("prepare payload bytes: %s\n", string(p.Bytes()))
 
 	repos, err := parseRepos(p.Type(), p.Bytes())
 	if err != nil {
 		return errors.New("error unmarshalling installation event: " + err.Error())
 	}
 
-	log.Printf("repositories: %+v\n", repos)
+	r.Printf("repositories: %+v\n", repos)
 	for _, repo := range repos {
-		log.Printf("repository: %s\n", *repo.FullName)
-
-		config := configObj{}
-		if err := yaml.Unmarshal([]byte(p.Config()), &config); err != nil {
-			return fmt.Errorf("error parsing heupr config: %s", err.Error())
-		}
+		r.Printf("repository: %s\n", *repo.FullName)
 
 		fullName := strings.Split(*repo.FullName, "/")
-		issues, err := b.help.listIssues(b.github, fullName[0], fullName[1])
+		issues, err := b.help.listIssues(ctx, b.github, fullName[0], fullName[1])
 		if err != nil {
 			return fmt.Errorf("error getting issues: %s", err.Error())
 		}
-		log.Printf("issues: %+v, count: %d\n", issues, len(issues))
+		r.Printf("issues: %+v, count: %d\n", issues, len(issues))
 
 		indexContent := make(map[string]string)
 		for _, issue := range issues {
@@ -136,11 +130,11 @@ func (b *bnkd) Prepare(p backend.Payload) error {
 				indexContent[actor] = b.help.getText(issue)
 			}
 		}
-		log.Printf("index content: %+v", indexContent)
+		r.Printf("index content: %+v", indexContent)
 
 		bleveClient := newClient()
 		for actor, corpus := range indexContent {
-			log.Printf("actor: %s, corpus: %s\n", actor, corpus)
+			r.Printf("actor: %s, corpus: %s\n", actor, corpus)
 			path := "/tmp/" + strings.Replace(*repo.FullName, "/", "_", -1) + ".bleve"
 			if err := bleveClient.index(path, actor, corpus); err != nil {
 				return fmt.Errorf("error indexing key/value: %s", err.Error())
@@ -148,15 +142,15 @@ func (b *bnkd) Prepare(p backend.Payload) error {
 		}
 	}
 
-	log.Println("successful prepare invocation")
+	r.Printf("successful prepare invocation\n")
 	return nil
 }
 
 // Act processes new issues and assigns available contributors
-func (b *bnkd) Act(p backend.Payload) error {
-	log.Printf("act payload bytes: %s\n", string(p.Bytes()))
+func (b *bnkd) Act(ctx context.Context, r backend.Request, p backend.Payload) error {
+	r.Printf("act payload bytes: %s\n", string(p.Bytes()))
 	if p.Type() != "issues" {
-		log.Printf("type %s not supported for issue assignment\n", p.Type())
+		r.Printf("type %s not supported for issue assignment\n", p.Type())
 		return nil
 	}
 
@@ -165,29 +159,22 @@ func (b *bnkd) Act(p backend.Payload) error {
 		return fmt.Errorf("error parsing issue: %s", err.Error())
 	}
 
-	log.Printf("action: %s\n", *event.Action)
+	r.Printf("action: %s\n", *event.Action)
 	if *event.Action != "opened" {
 		return nil // (?)
 	}
 
 	corpus := b.help.getText(event.Issue)
-	log.Printf("corpus: %s\n", corpus)
+	r.Printf("corpus: %s\n", corpus)
 
-	log.Printf("repository: %s\n", *event.Repo.FullName)
+	r.Printf("repository: %s\n", *event.Repo.FullName)
 	fullName := strings.Split(*event.Repo.FullName, "/")
 
-	config := configObj{}
-	if err := yaml.Unmarshal(p.Config(), &config); err != nil {
-		return fmt.Errorf("error parsing heupr config: %s", err.Error())
+	s := settings{}
+	if err := p.Settings(&s); err != nil {
+		return fmt.Errorf("error parsing settings: %s", err.Error())
 	}
-
-	contributors := []string{}
-	for _, bknd := range config.Backends {
-		if bknd.Name == "assignissue" {
-			contributors = bknd.Settings.Contributors
-		}
-	}
-	log.Printf("contributors: %v\n", contributors)
+	r.Printf("contributors: %v\n", s.Contributors)
 
 	bleveClient := newClient()
 	path := "/tmp/" + strings.Replace(*event.Repo.FullName, "/", "_", -1) + ".bleve"
@@ -195,18 +182,16 @@ func (b *bnkd) Act(p backend.Payload) error {
 	if err != nil {
 		return fmt.Errorf("error searching index: %s", err.Error())
 	}
-	log.Printf("actor: %s\n", actor)
+	r.Printf("actor: %s\n", actor)
 
-	for _, contributor := range contributors {
+	for _, contributor := range s.Contributors {
 		if contributor == actor {
-			if err := b.help.addAssignee(b.github, fullName[0], fullName[1], actor, *event.Issue.Number); err != nil {
+			if err := b.help.addAssignee(ctx, b.github, fullName[0], fullName[1], actor, *event.Issue.Number); err != nil {
 				return fmt.Errorf("error adding assignee: %s", err.Error())
 			}
 		}
 	}
 
-	log.Println("successful act invocation")
+	r.Printf("successful act invocation\n")
 	return nil
 }
-
-func main() {}
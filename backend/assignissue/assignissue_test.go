@@ -1,6 +1,7 @@
-package main
+package assignissue
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"log"
@@ -8,6 +9,9 @@ import (
 	"testing"
 
 	"github.com/google/go-github/v28/github"
+	"gopkg.in/yaml.v3"
+
+	"github.com/heupr/heupr/backend"
 )
 
 func TestMain(m *testing.M) {
@@ -16,9 +20,10 @@ func TestMain(m *testing.M) {
 }
 
 type mockPayload struct {
-	payloadBytes  string
-	payloadType   string
-	payloadConfig string
+	payloadBytes    string
+	payloadType     string
+	payloadConfig   string
+	payloadSettings string
 }
 
 func (m *mockPayload) Type() string {
@@ -33,6 +38,14 @@ func (m *mockPayload) Config() []byte {
 	return []byte(m.payloadConfig)
 }
 
+func (m *mockPayload) Settings(v interface{}) error {
+	return yaml.Unmarshal([]byte(m.payloadSettings), v)
+}
+
+func (m *mockPayload) ConfigSource() string {
+	return ""
+}
+
 type mockHelp struct {
 	listIssuesOutput []*github.Issue
 	listIssuesErr    error
@@ -64,11 +77,11 @@ func (m *mockHelp) getIndex(path string) error {
 	return nil
 }
 
-func (m *mockHelp) listIssues(c *github.Client, owner, repo string) ([]*github.Issue, error) {
+func (m *mockHelp) listIssues(ctx context.Context, c *github.Client, owner, repo string) ([]*github.Issue, error) {
 	return m.listIssuesOutput, m.listIssuesErr
 }
 
-func (m *mockHelp) getContent(c *github.Client, owner, repo, path string) (string, error) {
+func (m *mockHelp) getContent(ctx context.Context, c *github.Client, owner, repo, path string) (string, error) {
 	return m.getContentOutput, m.getContentErr
 }
 
@@ -76,7 +89,7 @@ func (m *mockHelp) getText(issue *github.Issue) string {
 	return m.getTextOutput
 }
 
-func (m *mockHelp) addAssignee(c *github.Client, owner, repo, actor string, number int) error {
+func (m *mockHelp) addAssignee(ctx context.Context, c *github.Client, owner, repo, actor string, number int) error {
 	return m.addAssigneeErr
 }
 
@@ -111,16 +124,6 @@ func TestPrepare(t *testing.T) {
 			newClientOutput:  nil,
 			err:              "error getting heupr config: mock get content error",
 		},
-		{
-			desc:             "error unmarshalling config file",
-			payloadBytes:     `{"repositories_added":[{"full_name":"delta-squad/CC-1038"}]}`,
-			getContentOutput: "-------",
-			getContentErr:    nil,
-			listIssuesOutput: nil,
-			listIssuesErr:    nil,
-			newClientOutput:  nil,
-			err:              "error parsing heupr config: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `-------` into main.configObj",
-		},
 		{
 			desc:             "error listing issues",
 			payloadBytes:     `{"repositories_added":[{"full_name":"delta-squad/CC-1038"}]}`,
@@ -191,12 +194,12 @@ func TestPrepare(t *testing.T) {
 			return test.newClientOutput
 		}
 
-		b := Backend
+		b := bnkd{}
 
 		b.help = h
 		b.github = github.NewClient(nil)
 
-		err := b.Prepare(p)
+		err := b.Prepare(context.Background(), backend.Request{}, p)
 		if err != nil && err.Error() != test.err {
 			t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
 		}
@@ -209,7 +212,7 @@ func TestAct(t *testing.T) {
 		payloadBytes    string
 		payloadType     string
 		getTextOutput   string
-		payloadConfig   string
+		payloadSettings string
 		newClientOutput assigner
 		addAssigneeErr  error
 		err             string
@@ -219,7 +222,7 @@ func TestAct(t *testing.T) {
 			payloadBytes:    "",
 			payloadType:     "",
 			getTextOutput:   "",
-			payloadConfig:   "",
+			payloadSettings: "",
 			newClientOutput: nil,
 			addAssigneeErr:  nil,
 			err:             "",
@@ -229,7 +232,7 @@ func TestAct(t *testing.T) {
 			payloadBytes:    "[]",
 			payloadType:     "issues",
 			getTextOutput:   "",
-			payloadConfig:   "",
+			payloadSettings: "",
 			newClientOutput: nil,
 			addAssigneeErr:  nil,
 			err:             "error parsing issue: json: cannot unmarshal array into Go value of type github.IssuesEvent",
@@ -239,7 +242,7 @@ func TestAct(t *testing.T) {
 			payloadBytes:    `{"action":"closed"}`,
 			payloadType:     "issues",
 			getTextOutput:   "",
-			payloadConfig:   "",
+			payloadSettings: "",
 			newClientOutput: nil,
 			addAssigneeErr:  nil,
 			err:             "",
@@ -249,17 +252,17 @@ func TestAct(t *testing.T) {
 			payloadBytes:    `{"action":"opened","issue":{"title":"battle of geonosis","body":"the beginning of the war"},"repository":{"full_name": "grand-plan/dooku"}}`,
 			payloadType:     "issues",
 			getTextOutput:   "issue corpus",
-			payloadConfig:   "-------",
+			payloadSettings: "-------",
 			newClientOutput: nil,
 			addAssigneeErr:  nil,
-			err:             "error parsing heupr config: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `-------` into main.configObj",
+			err:             "error parsing settings: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `-------` into assignissue.settings",
 		},
 		{
-			desc:          "error searching value",
-			payloadBytes:  `{"action":"opened","issue":{"title":"battle of geonosis","body":"the beginning of the war"},"repository":{"full_name": "grand-plan/dooku"}}`,
-			payloadType:   "issues",
-			getTextOutput: "issue corpus",
-			payloadConfig: "",
+			desc:            "error searching value",
+			payloadBytes:    `{"action":"opened","issue":{"title":"battle of geonosis","body":"the beginning of the war"},"repository":{"full_name": "grand-plan/dooku"}}`,
+			payloadType:     "issues",
+			getTextOutput:   "issue corpus",
+			payloadSettings: "",
 			newClientOutput: &mockBleve{
 				searchOutput: "",
 				searchErr:    errors.New("mock search error"),
@@ -268,11 +271,11 @@ func TestAct(t *testing.T) {
 			err:            "error searching index: mock search error",
 		},
 		{
-			desc:          "add assignee error",
-			payloadBytes:  `{"action":"opened","issue":{"title":"battle of geonosis","body":"the beginning of the war"},"repository":{"full_name": "grand-plan/dooku"}}`,
-			payloadType:   "issues",
-			getTextOutput: "issue corpus",
-			payloadConfig: `{backends: [{name: assignissue, events: [{name: issues, actions: [opened]}], settings: {contributors: [example_github_username]}}]}`,
+			desc:            "add assignee error",
+			payloadBytes:    `{"action":"opened","issue":{"title":"battle of geonosis","body":"the beginning of the war"},"repository":{"full_name": "grand-plan/dooku"}}`,
+			payloadType:     "issues",
+			getTextOutput:   "issue corpus",
+			payloadSettings: `{contributors: [example_github_username]}`,
 			newClientOutput: &mockBleve{
 				searchOutput: "grandmaster yoda",
 				searchErr:    nil,
@@ -281,11 +284,11 @@ func TestAct(t *testing.T) {
 			err:            "error searching index: mock search error",
 		},
 		{
-			desc:          "successful invocation",
-			payloadBytes:  `{"action":"opened","issue":{"number": 2,"title":"battle of geonosis","body":"the beginning of the war"},"repository":{"full_name":"grand-plan/dooku"}}`,
-			payloadType:   "issues",
-			getTextOutput: "issue corpus",
-			payloadConfig: `{backends: [{name: assignissue, events: [{name: issues, actions: [opened]}], settings: {contributors: [example_github_username]}}]}`,
+			desc:            "successful invocation",
+			payloadBytes:    `{"action":"opened","issue":{"number": 2,"title":"battle of geonosis","body":"the beginning of the war"},"repository":{"full_name":"grand-plan/dooku"}}`,
+			payloadType:     "issues",
+			getTextOutput:   "issue corpus",
+			payloadSettings: `{contributors: [example_github_username]}`,
 			newClientOutput: &mockBleve{
 				searchOutput: "yoda",
 				searchErr:    nil,
@@ -297,9 +300,9 @@ func TestAct(t *testing.T) {
 
 	for _, test := range tests {
 		p := &mockPayload{
-			payloadBytes:  test.payloadBytes,
-			payloadType:   test.payloadType,
-			payloadConfig: test.payloadConfig,
+			payloadBytes:    test.payloadBytes,
+			payloadType:     test.payloadType,
+			payloadSettings: test.payloadSettings,
 		}
 
 		newClient = func() assigner {
@@ -313,17 +316,13 @@ func TestAct(t *testing.T) {
 			addAssigneeErr: test.addAssigneeErr,
 		}
 
-		b := Backend
+		b := bnkd{}
 
 		b.help = h
 
-		err := b.Act(p)
+		err := b.Act(context.Background(), backend.Request{}, p)
 		if err != nil && err.Error() != test.err {
 			t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
 		}
 	}
 }
-
-func Test_main(t *testing.T) {
-	main() // invoking for test coverage
-}
@@ -1,4 +1,4 @@
-package main
+package assignissue
 
 import (
 	"context"
@@ -12,17 +12,17 @@ import (
 )
 
 type helper interface {
-	getContent(c *github.Client, owner, repo, path string) (string, error)
+	getContent(ctx context.Context, c *github.Client, owner, repo, path string) (string, error)
 	getText(issue *github.Issue) string
-	listIssues(c *github.Client, owner, repo string) ([]*github.Issue, error)
-	addAssignee(c *github.Client, owner, repo, actor string, number int) error
+	listIssues(ctx context.Context, c *github.Client, owner, repo string) ([]*github.Issue, error)
+	addAssignee(ctx context.Context, c *github.Client, owner, repo, actor string, number int) error
 }
 
 type help struct{}
 
-func (h *help) getContent(c *github.Client, owner, repo, path string) (string, error) {
+func (h *help) getContent(ctx context.Context, c *github.Client, owner, repo, path string) (string, error) {
 	opts := &github.RepositoryContentGetOptions{}
-	file, _, _, err := c.Repositories.GetContents(context.Background(), owner, repo, path, opts)
+	file, _, _, err := c.Repositories.GetContents(ctx, owner, repo, path, opts)
 	if err != nil {
 		return "", errors.New("error getting content: " + err.Error())
 	}
@@ -43,12 +43,12 @@ func (h *help) getText(issue *github.Issue) string {
 	return title + " " + body
 }
 
-func (h *help) addAssignee(c *github.Client, owner, repo, actor string, number int) error {
-	_, _, err := c.Issues.AddAssignees(context.Background(), owner, repo, number, []string{actor})
+func (h *help) addAssignee(ctx context.Context, c *github.Client, owner, repo, actor string, number int) error {
+	_, _, err := c.Issues.AddAssignees(ctx, owner, repo, number, []string{actor})
 	return err
 }
 
-func (h *help) listIssues(c *github.Client, owner, repo string) ([]*github.Issue, error) {
+func (h *help) listIssues(ctx context.Context, c *github.Client, owner, repo string) ([]*github.Issue, error) {
 	output := []*github.Issue{}
 
 	opts := &github.IssueListByRepoOptions{
@@ -60,7 +60,7 @@ func (h *help) listIssues(c *github.Client, owner, repo string) ([]*github.Issue
 	}
 
 	for {
-		issues, resp, err := c.Issues.ListByRepo(context.Background(), owner, repo, opts)
+		issues, resp, err := c.Issues.ListByRepo(ctx, owner, repo, opts)
 		if err != nil {
 			return nil, err
 		}
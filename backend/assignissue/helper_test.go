@@ -1,6 +1,7 @@
-package main
+package assignissue
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -25,7 +26,7 @@ func Test_getContent(t *testing.T) {
 
 	h := help{}
 
-	output, err := h.getContent(c, "mustafar", "mining-facility", "confederacy-leadership.json")
+	output, err := h.getContent(context.Background(), c, "mustafar", "mining-facility", "confederacy-leadership.json")
 	if output == "" {
 		t.Errorf("description: error getting file contents, received: %s", output)
 	}
@@ -68,7 +69,7 @@ func Test_listIssues(t *testing.T) {
 	c.UploadURL = url
 
 	h := &help{}
-	issues, err := h.listIssues(c, "hoth", "echo-base")
+	issues, err := h.listIssues(context.Background(), c, "hoth", "echo-base")
 	if err != nil {
 		t.Errorf("description: error listing issues, error received: %s", err.Error())
 	}
@@ -96,7 +97,7 @@ func Test_addAssignees(t *testing.T) {
 	c.UploadURL = url
 
 	h := help{}
-	if err := h.addAssignee(c, "lars-homestead", "new-droids", "luke", 1); err != nil {
+	if err := h.addAssignee(context.Background(), c, "lars-homestead", "new-droids", "luke", 1); err != nil {
 		t.Errorf("description: error assigning contributor, error received: %s", err.Error())
 	}
 }
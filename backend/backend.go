@@ -1,17 +1,142 @@
 package backend
 
-import "github.com/google/go-github/v28/github"
+import (
+	"context"
+	"log"
+
+	"github.com/google/go-github/v28/github"
+)
 
 // Payload defines the value passed between frontend resources and backend packages
 type Payload interface {
 	Type() string
 	Bytes() []byte
 	Config() []byte
+	Settings(v interface{}) error
+	ConfigSource() string
+}
+
+// Configurable is optionally implemented by backends accepting a settings block, returning a pointer to its zero value
+type Configurable interface {
+	Settings() interface{}
+}
+
+// Manifest describes the capabilities a backend declares to the application
+type Manifest struct {
+	// Events lists the webhook event types passed to Act
+	Events []string
+}
+
+// Manifester is optionally implemented by backends declaring a manifest
+type Manifester interface {
+	Manifest() Manifest
+}
+
+// DefaultEvents lists the event types passed to Act for backends without a manifest
+var DefaultEvents = []string{
+	"issues",
+	"pull_request",
+	"project",
+	"project_card",
+	"project_column",
+}
+
+// Handles reports whether the backend accepts the event type in Act
+func Handles(b BackendV2, eventType string) bool {
+	return manifest(b).Handles(eventType)
+}
+
+// Handles reports whether the manifest lists the event type
+func (m Manifest) Handles(eventType string) bool {
+	for _, event := range m.Events {
+		if event == eventType {
+			return true
+		}
+	}
+
+	return false
 }
 
 // Backend defines the contract packages must follow for use with the application
+//
+// Deprecated: new packages should implement BackendV2; existing
+// implementations can be converted with Adapt.
 type Backend interface {
 	Configure(*github.Client)
 	Prepare(Payload) error
 	Act(Payload) error
 }
+
+// Request carries the per-invocation values passed to BackendV2 methods
+type Request struct {
+	Backend    string
+	DeliveryID string
+	Event      string
+	Repo       string
+	Logger     *log.Logger
+}
+
+// Printf logs to the request logger or the standard logger if none is set
+func (r Request) Printf(format string, v ...interface{}) {
+	if r.Logger == nil {
+		log.Printf(format, v...)
+		return
+	}
+	r.Logger.Printf(format, v...)
+}
+
+// BackendV2 defines the context-aware contract packages must follow, with a context cancelled at the backend deadline
+type BackendV2 interface {
+	Configure(ctx context.Context, r Request, c *github.Client)
+	Prepare(ctx context.Context, r Request, p Payload) error
+	Act(ctx context.Context, r Request, p Payload) error
+}
+
+// Adapt wraps a Backend implementation so it satisfies the BackendV2 interface
+func Adapt(b Backend) BackendV2 {
+	return &adapter{
+		backend: b,
+	}
+}
+
+type adapter struct {
+	backend Backend
+}
+
+func (a *adapter) Configure(ctx context.Context, r Request, c *github.Client) {
+	a.backend.Configure(c)
+}
+
+func (a *adapter) Prepare(ctx context.Context, r Request, p Payload) error {
+	return a.backend.Prepare(p)
+}
+
+func (a *adapter) Act(ctx context.Context, r Request, p Payload) error {
+	return a.backend.Act(p)
+}
+
+// Manifest forwards the manifest of the wrapped backend
+func (a *adapter) Manifest() Manifest {
+	return manifest(a.backend)
+}
+
+// Settings forwards the settings type of the wrapped backend
+func (a *adapter) Settings() interface{} {
+	return settings(a.backend)
+}
+
+// manifest returns the manifest of the value, or the default events if it declares none, so wrappers can forward it
+func manifest(v interface{}) Manifest {
+	if manifester, ok := v.(Manifester); ok {
+		return manifester.Manifest()
+	}
+	return Manifest{Events: DefaultEvents}
+}
+
+// settings returns the settings type of the value or nil if it accepts no settings block
+func settings(v interface{}) interface{} {
+	if configurable, ok := v.(Configurable); ok {
+		return configurable.Settings()
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+)
+
+func TestAdapt(t *testing.T) {
+	tb := &testBackend{
+		prepareErr: errors.New("mock prepare error"),
+		actErr:     errors.New("mock act error"),
+	}
+
+	b := Adapt(tb)
+	ctx := context.Background()
+
+	b.Configure(ctx, Request{}, github.NewClient(nil))
+	if !tb.configured {
+		t.Error("description: adapted backend not configured")
+	}
+
+	if err := b.Prepare(ctx, Request{}, nil); err == nil || err.Error() != "mock prepare error" {
+		t.Errorf("description: incorrect prepare error, received: %v", err)
+	}
+
+	if err := b.Act(ctx, Request{}, nil); err == nil || err.Error() != "mock act error" {
+		t.Errorf("description: incorrect act error, received: %v", err)
+	}
+}
+
+type manifestBackend struct {
+	BackendV2
+	events []string
+}
+
+func (mb *manifestBackend) Manifest() Manifest {
+	return Manifest{
+		Events: mb.events,
+	}
+}
+
+func TestHandles(t *testing.T) {
+	tests := []struct {
+		desc      string
+		bknd      BackendV2
+		eventType string
+		expected  bool
+	}{
+		{
+			desc:      "default event without manifest",
+			bknd:      Adapt(&testBackend{}),
+			eventType: "issues",
+			expected:  true,
+		},
+		{
+			desc:      "other event without manifest",
+			bknd:      Adapt(&testBackend{}),
+			eventType: "push",
+			expected:  false,
+		},
+		{
+			desc:      "event declared in manifest",
+			bknd:      &manifestBackend{events: []string{"push"}},
+			eventType: "push",
+			expected:  true,
+		},
+		{
+			desc:      "default event not declared in manifest",
+			bknd:      &manifestBackend{events: []string{"push"}},
+			eventType: "issues",
+			expected:  false,
+		},
+	}
+
+	for _, test := range tests {
+		if received := Handles(test.bknd, test.eventType); received != test.expected {
+			t.Errorf("description: %s, received: %t, expected: %t", test.desc, received, test.expected)
+		}
+	}
+}
+
+type declaredBackend struct {
+	testBackend
+}
+
+func (db *declaredBackend) Manifest() Manifest {
+	return Manifest{
+		Events: []string{"push"},
+	}
+}
+
+type declaredSettings struct {
+	Label string `yaml:"label"`
+}
+
+func (db *declaredBackend) Settings() interface{} {
+	return &declaredSettings{}
+}
+
+func TestWrappersForward(t *testing.T) {
+	tests := []struct {
+		desc     string
+		bknd     BackendV2
+		push     bool
+		settings interface{}
+	}{
+		{
+			desc:     "adapted backend without optional interfaces",
+			bknd:     Adapt(&testBackend{}),
+			push:     false,
+			settings: nil,
+		},
+		{
+			desc:     "adapted backend with optional interfaces",
+			bknd:     Adapt(&declaredBackend{}),
+			push:     true,
+			settings: &declaredSettings{},
+		},
+		{
+			desc:     "shared adapted backend with optional interfaces",
+			bknd:     shared(Adapt(&declaredBackend{}))(),
+			push:     true,
+			settings: &declaredSettings{},
+		},
+	}
+
+	for _, test := range tests {
+		if received := Handles(test.bknd, "push"); received != test.push {
+			t.Errorf("description: %s, handles push received: %t, expected: %t", test.desc, received, test.push)
+		}
+
+		configurable, ok := test.bknd.(Configurable)
+		if !ok {
+			t.Errorf("description: %s, wrapper does not forward settings", test.desc)
+			continue
+		}
+
+		if received := configurable.Settings(); !reflect.DeepEqual(received, test.settings) {
+			t.Errorf("description: %s, settings received: %#v, expected: %#v", test.desc, received, test.settings)
+		}
+	}
+}
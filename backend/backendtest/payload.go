@@ -0,0 +1,165 @@
+package backendtest
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/google/go-github/v28/github"
+
+	"github.com/heupr/heupr/backend"
+	"github.com/heupr/heupr/config"
+)
+
+// Payload is a backend.Payload built from a webhook event and optional config file
+type Payload struct {
+	EventType string
+	Body      []byte
+	Content   []byte
+	Source    string
+
+	settings config.Backend
+	err      error
+}
+
+// NewPayload creates a payload for the event which is encoded as JSON unless given as bytes or a string
+func NewPayload(eventType string, event interface{}) *Payload {
+	p := &Payload{
+		EventType: eventType,
+	}
+
+	switch e := event.(type) {
+	case []byte:
+		p.Body = e
+	case string:
+		p.Body = []byte(e)
+	default:
+		p.Body, p.err = json.Marshal(e)
+	}
+
+	return p
+}
+
+// WithConfig sets the config file and the settings block of the named backend
+func (p *Payload) WithConfig(name, content string) *Payload {
+	p.Content = []byte(content)
+	if p.Source == "" {
+		p.Source = ".heupr.yml"
+	}
+
+	cfg, err := config.Parse(p.Content)
+	if err != nil {
+		p.err = err
+		return p
+	}
+
+	p.settings, _ = cfg.Backend(name)
+	return p
+}
+
+// Type returns the webhook event type
+func (p *Payload) Type() string {
+	return p.EventType
+}
+
+// Bytes returns the webhook event body
+func (p *Payload) Bytes() []byte {
+	return p.Body
+}
+
+// Config returns the config file content
+func (p *Payload) Config() []byte {
+	return p.Content
+}
+
+// Settings decodes the backend settings block into the provided value
+func (p *Payload) Settings(v interface{}) error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.settings.Decode(v)
+}
+
+// ConfigSource returns where the config file was loaded from
+func (p *Payload) ConfigSource() string {
+	return p.Source
+}
+
+var _ backend.Payload = &Payload{}
+
+// NewRequest creates the request values passed to the named backend for the event
+func NewRequest(name, eventType, fullName string) backend.Request {
+	return backend.Request{
+		Backend:    name,
+		DeliveryID: "backendtest",
+		Event:      eventType,
+		Repo:       fullName,
+	}
+}
+
+// Repository creates a repository with the full name split into owner and name
+func Repository(fullName string) *github.Repository {
+	split := strings.SplitN(fullName, "/", 2)
+	output := &github.Repository{
+		FullName: github.String(fullName),
+		Name:     github.String(split[len(split)-1]),
+	}
+	if len(split) == 2 {
+		output.Owner = &github.User{Login: github.String(split[0])}
+	}
+	return output
+}
+
+// InstallationPayload creates an installation event payload for the repositories
+func InstallationPayload(appID int64, fullNames ...string) *Payload {
+	repos := []*github.Repository{}
+	for _, fullName := range fullNames {
+		repos = append(repos, Repository(fullName))
+	}
+
+	return NewPayload("installation", &github.InstallationEvent{
+		Action: github.String("created"),
+		Installation: &github.Installation{
+			ID:    github.Int64(1),
+			AppID: github.Int64(appID),
+		},
+		Repositories: repos,
+	})
+}
+
+// IssuesPayload creates an issues event payload for the issue
+func IssuesPayload(action, fullName string, issue *github.Issue) *Payload {
+	return NewPayload("issues", &github.IssuesEvent{
+		Action: github.String(action),
+		Issue:  issue,
+		Repo:   Repository(fullName),
+	})
+}
+
+// PullRequestPayload creates a pull_request event payload for the pull request
+func PullRequestPayload(action, fullName string, pr *github.PullRequest) *Payload {
+	return NewPayload("pull_request", &github.PullRequestEvent{
+		Action:      github.String(action),
+		Number:      pr.Number,
+		PullRequest: pr,
+		Repo:        Repository(fullName),
+	})
+}
+
+// IssueCommentPayload creates an issue_comment event payload for the comment on the issue
+func IssueCommentPayload(action, fullName string, issue *github.Issue, comment *github.IssueComment) *Payload {
+	return NewPayload("issue_comment", &github.IssueCommentEvent{
+		Action:  github.String(action),
+		Issue:   issue,
+		Comment: comment,
+		Repo:    Repository(fullName),
+	})
+}
+
+// ProjectCardPayload creates a project_card event payload for the card
+func ProjectCardPayload(action, fullName string, card *github.ProjectCard) *Payload {
+	return NewPayload("project_card", &github.ProjectCardEvent{
+		Action:      github.String(action),
+		ProjectCard: card,
+		Repo:        Repository(fullName),
+	})
+}
@@ -0,0 +1,72 @@
+package backendtest
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+	"github.com/tidwall/gjson"
+)
+
+func TestPayload(t *testing.T) {
+	type settings struct {
+		Prefix string `yaml:"prefix"`
+	}
+
+	tests := []struct {
+		desc    string
+		payload *Payload
+		path    string
+		value   string
+		config  string
+		prefix  string
+		err     bool
+	}{
+		{
+			desc:    "raw event body",
+			payload: NewPayload("ping", `{"zen":"patience"}`),
+			path:    "zen",
+			value:   "patience",
+		},
+		{
+			desc:    "pull request event",
+			payload: PullRequestPayload("closed", "jedi/temple", &github.PullRequest{Number: github.Int(66)}),
+			path:    "repository.owner.login",
+			value:   "jedi",
+		},
+		{
+			desc: "backend settings",
+			payload: IssuesPayload("opened", "jedi/temple", &github.Issue{Number: github.Int(66)}).WithConfig("estimatepr", `
+backends:
+- name: estimatepr
+  settings:
+    prefix: est-
+`),
+			path:   "issue.number",
+			value:  "66",
+			prefix: "est-",
+		},
+		{
+			desc:    "invalid config",
+			payload: InstallationPayload(1, "jedi/temple").WithConfig("estimatepr", "backends: [\n"),
+			path:    "repositories.0.full_name",
+			value:   "jedi/temple",
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		if value := gjson.GetBytes(test.payload.Bytes(), test.path).String(); value != test.value {
+			t.Errorf("description: %s, value received: %s, expected: %s", test.desc, value, test.value)
+		}
+
+		s := settings{}
+		err := test.payload.Settings(&s)
+		if (err != nil) != test.err {
+			t.Errorf("description: %s, error received: %v, expected: %t", test.desc, err, test.err)
+		}
+
+		if s.Prefix != test.prefix {
+			t.Errorf("description: %s, prefix received: %s, expected: %s", test.desc, s.Prefix, test.prefix)
+		}
+	}
+}
@@ -0,0 +1,564 @@
+// Package backendtest provides a fake GitHub API and payload helpers for testing backends end-to-end.
+package backendtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v28/github"
+)
+
+// Repo holds the fixtures served for a single repository, updated in place by writes
+type Repo struct {
+	Issues       []*github.Issue
+	PullRequests []*github.PullRequest
+	Labels       []*github.Label
+	// Comments are the issue and pull request comments keyed by number
+	Comments map[int][]*github.IssueComment
+	// Commits are the pull request commits keyed by number
+	Commits map[int][]*github.RepositoryCommit
+	// Files are the file contents keyed by path
+	Files map[string]string
+}
+
+// Request records a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// Server is a fake GitHub REST API serving the fixtures of a set of repositories
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	repos    map[string]*Repo
+	cards    map[int64]*github.ProjectCard
+	columns  map[int64][]int64
+	nextID   int64
+	requests []Request
+}
+
+// NewServer starts a fake GitHub API server which must be closed by the caller
+func NewServer() *Server {
+	s := &Server{
+		repos:   make(map[string]*Repo),
+		cards:   make(map[int64]*github.ProjectCard),
+		columns: make(map[int64][]int64),
+		nextID:  1000,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Client returns a GitHub client sending requests to the server
+func (s *Server) Client() *github.Client {
+	c := github.NewClient(nil)
+	u, _ := url.Parse(s.URL + "/")
+	c.BaseURL = u
+	c.UploadURL = u
+	return c
+}
+
+// Repo returns the fixtures for the repository, creating them if needed
+func (s *Server) Repo(fullName string) *Repo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.repo(fullName)
+}
+
+func (s *Server) repo(fullName string) *Repo {
+	r, ok := s.repos[fullName]
+	if !ok {
+		r = &Repo{
+			Comments: make(map[int][]*github.IssueComment),
+			Commits:  make(map[int][]*github.RepositoryCommit),
+			Files:    make(map[string]string),
+		}
+		s.repos[fullName] = r
+	}
+	return r
+}
+
+// AddCard adds the project card fixture to the column
+func (s *Server) AddCard(columnID int64, card *github.ProjectCard) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if card.ID == nil {
+		card.ID = github.Int64(s.id())
+	}
+	card.ColumnID = github.Int64(columnID)
+	s.cards[card.GetID()] = card
+	s.columns[columnID] = append(s.columns[columnID], card.GetID())
+}
+
+// Card returns the project card fixture with the ID
+func (s *Server) Card(id int64) *github.ProjectCard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cards[id]
+}
+
+// Requests returns every request received by the server in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request{}, s.requests...)
+}
+
+// Writes returns the requests received by the server which were not reads
+func (s *Server) Writes() []Request {
+	output := []Request{}
+	for _, req := range s.Requests() {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			output = append(output, req)
+		}
+	}
+	return output
+}
+
+func (s *Server) id() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handle  func(s *Server, w http.ResponseWriter, r *http.Request, params []string, body []byte)
+}
+
+const repoPattern = `/repos/([^/]+/[^/]+)`
+
+var routes = []route{
+	{http.MethodGet, regexp.MustCompile(`^` + repoPattern + `/issues$`), (*Server).listIssues},
+	{http.MethodPost, regexp.MustCompile(`^` + repoPattern + `/issues$`), (*Server).createIssue},
+	{http.MethodGet, regexp.MustCompile(`^` + repoPattern + `/issues/(\d+)$`), (*Server).getIssue},
+	{http.MethodPost, regexp.MustCompile(`^` + repoPattern + `/issues/(\d+)/assignees$`), (*Server).addAssignees},
+	{http.MethodGet, regexp.MustCompile(`^` + repoPattern + `/issues/(\d+)/comments$`), (*Server).listComments},
+	{http.MethodPost, regexp.MustCompile(`^` + repoPattern + `/issues/(\d+)/comments$`), (*Server).createComment},
+	{http.MethodGet, regexp.MustCompile(`^` + repoPattern + `/issues/(\d+)/labels$`), (*Server).listIssueLabels},
+	{http.MethodPost, regexp.MustCompile(`^` + repoPattern + `/issues/(\d+)/labels$`), (*Server).addIssueLabels},
+	{http.MethodDelete, regexp.MustCompile(`^` + repoPattern + `/issues/(\d+)/labels/([^/]+)$`), (*Server).removeIssueLabel},
+	{http.MethodGet, regexp.MustCompile(`^` + repoPattern + `/labels$`), (*Server).listLabels},
+	{http.MethodPost, regexp.MustCompile(`^` + repoPattern + `/labels$`), (*Server).createLabel},
+	{http.MethodGet, regexp.MustCompile(`^` + repoPattern + `/pulls$`), (*Server).listPullRequests},
+	{http.MethodGet, regexp.MustCompile(`^` + repoPattern + `/pulls/(\d+)$`), (*Server).getPullRequest},
+	{http.MethodGet, regexp.MustCompile(`^` + repoPattern + `/pulls/(\d+)/commits$`), (*Server).listCommits},
+	{http.MethodGet, regexp.MustCompile(`^` + repoPattern + `/contents/(.+)$`), (*Server).getContents},
+	{http.MethodGet, regexp.MustCompile(`^/projects/columns/cards/(\d+)$`), (*Server).getCard},
+	{http.MethodPost, regexp.MustCompile(`^/projects/columns/cards/(\d+)/moves$`), (*Server).moveCard},
+	{http.MethodGet, regexp.MustCompile(`^/projects/columns/(\d+)/cards$`), (*Server).listCards},
+	{http.MethodPost, regexp.MustCompile(`^/projects/columns/(\d+)/cards$`), (*Server).createCard},
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Body:   strings.TrimSpace(string(body)),
+	})
+
+	for _, rt := range routes {
+		if rt.method != r.Method {
+			continue
+		}
+		if params := rt.pattern.FindStringSubmatch(r.URL.Path); params != nil {
+			rt.handle(s, w, r, params[1:], body)
+			return
+		}
+	}
+
+	notFound(w)
+}
+
+func notFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+}
+
+func badRequest(w http.ResponseWriter, err error) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Problems parsing JSON: " + err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writePage writes the requested page of the items, linking to the next page if any remain
+func writePage(w http.ResponseWriter, r *http.Request, items []interface{}) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 {
+		perPage = 30
+	}
+
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	stop := start + perPage
+	if stop >= len(items) {
+		stop = len(items)
+	} else {
+		next := *r.URL
+		query := next.Query()
+		query.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+	}
+
+	writeJSON(w, http.StatusOK, items[start:stop])
+}
+
+// matchesState reports whether the item state matches the state query parameter
+func matchesState(r *http.Request, state string) bool {
+	switch query := r.URL.Query().Get("state"); query {
+	case "all":
+		return true
+	case "":
+		return state == "" || state == "open"
+	default:
+		return state == query
+	}
+}
+
+func (s *Server) findIssue(fullName, number string) *github.Issue {
+	n, _ := strconv.Atoi(number)
+	for _, issue := range s.repo(fullName).Issues {
+		if issue.GetNumber() == n {
+			return issue
+		}
+	}
+	return nil
+}
+
+func (s *Server) listIssues(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	items := []interface{}{}
+	for _, issue := range s.repo(params[0]).Issues {
+		if matchesState(r, issue.GetState()) {
+			items = append(items, issue)
+		}
+	}
+	writePage(w, r, items)
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	req := github.IssueRequest{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+
+	repo := s.repo(params[0])
+	issue := &github.Issue{
+		ID:     github.Int64(s.id()),
+		Number: github.Int(len(repo.Issues) + len(repo.PullRequests) + 1),
+		Title:  req.Title,
+		Body:   req.Body,
+		State:  github.String("open"),
+	}
+	if req.Labels != nil {
+		for _, name := range *req.Labels {
+			issue.Labels = append(issue.Labels, github.Label{Name: github.String(name)})
+		}
+	}
+	if req.Assignees != nil {
+		for _, login := range *req.Assignees {
+			issue.Assignees = append(issue.Assignees, &github.User{Login: github.String(login)})
+		}
+	}
+
+	repo.Issues = append(repo.Issues, issue)
+	writeJSON(w, http.StatusCreated, issue)
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	issue := s.findIssue(params[0], params[1])
+	if issue == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, issue)
+}
+
+func (s *Server) addAssignees(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	issue := s.findIssue(params[0], params[1])
+	if issue == nil {
+		notFound(w)
+		return
+	}
+
+	req := struct {
+		Assignees []string `json:"assignees"`
+	}{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+
+	for _, login := range req.Assignees {
+		issue.Assignees = append(issue.Assignees, &github.User{Login: github.String(login)})
+	}
+	writeJSON(w, http.StatusCreated, issue)
+}
+
+func (s *Server) listComments(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	n, _ := strconv.Atoi(params[1])
+	items := []interface{}{}
+	for _, comment := range s.repo(params[0]).Comments[n] {
+		items = append(items, comment)
+	}
+	writePage(w, r, items)
+}
+
+func (s *Server) createComment(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	comment := &github.IssueComment{}
+	if err := json.Unmarshal(body, comment); err != nil {
+		badRequest(w, err)
+		return
+	}
+
+	n, _ := strconv.Atoi(params[1])
+	comment.ID = github.Int64(s.id())
+
+	repo := s.repo(params[0])
+	repo.Comments[n] = append(repo.Comments[n], comment)
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+func (s *Server) listIssueLabels(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	issue := s.findIssue(params[0], params[1])
+	if issue == nil {
+		notFound(w)
+		return
+	}
+
+	items := []interface{}{}
+	for _, label := range issue.Labels {
+		items = append(items, label)
+	}
+	writePage(w, r, items)
+}
+
+func (s *Server) addIssueLabels(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	issue := s.findIssue(params[0], params[1])
+	if issue == nil {
+		notFound(w)
+		return
+	}
+
+	names := []string{}
+	if err := json.Unmarshal(body, &names); err != nil {
+		badRequest(w, err)
+		return
+	}
+
+	for _, name := range names {
+		issue.Labels = append(issue.Labels, github.Label{Name: github.String(name)})
+	}
+	writeJSON(w, http.StatusOK, issue.Labels)
+}
+
+func (s *Server) removeIssueLabel(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	issue := s.findIssue(params[0], params[1])
+	if issue == nil {
+		notFound(w)
+		return
+	}
+
+	name, _ := url.PathUnescape(params[2])
+	labels := []github.Label{}
+	found := false
+	for _, label := range issue.Labels {
+		if label.GetName() == name {
+			found = true
+			continue
+		}
+		labels = append(labels, label)
+	}
+	if !found {
+		notFound(w)
+		return
+	}
+
+	issue.Labels = labels
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listLabels(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	items := []interface{}{}
+	for _, label := range s.repo(params[0]).Labels {
+		items = append(items, label)
+	}
+	writePage(w, r, items)
+}
+
+func (s *Server) createLabel(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	label := &github.Label{}
+	if err := json.Unmarshal(body, label); err != nil {
+		badRequest(w, err)
+		return
+	}
+
+	label.ID = github.Int64(s.id())
+
+	repo := s.repo(params[0])
+	repo.Labels = append(repo.Labels, label)
+	writeJSON(w, http.StatusCreated, label)
+}
+
+func (s *Server) findPullRequest(fullName, number string) *github.PullRequest {
+	n, _ := strconv.Atoi(number)
+	for _, pr := range s.repo(fullName).PullRequests {
+		if pr.GetNumber() == n {
+			return pr
+		}
+	}
+	return nil
+}
+
+func (s *Server) listPullRequests(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	items := []interface{}{}
+	for _, pr := range s.repo(params[0]).PullRequests {
+		if matchesState(r, pr.GetState()) {
+			items = append(items, pr)
+		}
+	}
+	writePage(w, r, items)
+}
+
+func (s *Server) getPullRequest(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	pr := s.findPullRequest(params[0], params[1])
+	if pr == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, pr)
+}
+
+func (s *Server) listCommits(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	if s.findPullRequest(params[0], params[1]) == nil {
+		notFound(w)
+		return
+	}
+
+	n, _ := strconv.Atoi(params[1])
+	items := []interface{}{}
+	for _, commit := range s.repo(params[0]).Commits[n] {
+		items = append(items, commit)
+	}
+	writePage(w, r, items)
+}
+
+func (s *Server) getContents(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	content, ok := s.repo(params[0]).Files[params[1]]
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &github.RepositoryContent{
+		Type:     github.String("file"),
+		Encoding: github.String("base64"),
+		Path:     github.String(params[1]),
+		Content:  github.String(base64.StdEncoding.EncodeToString([]byte(content))),
+		SHA:      github.String(fmt.Sprintf("%x", len(content))),
+	})
+}
+
+func (s *Server) getCard(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	id, _ := strconv.ParseInt(params[0], 10, 64)
+	card, ok := s.cards[id]
+	if !ok {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, card)
+}
+
+func (s *Server) moveCard(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	id, _ := strconv.ParseInt(params[0], 10, 64)
+	card, ok := s.cards[id]
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	opts := github.ProjectCardMoveOptions{}
+	if err := json.Unmarshal(body, &opts); err != nil {
+		badRequest(w, err)
+		return
+	}
+
+	if opts.ColumnID != 0 && opts.ColumnID != card.GetColumnID() {
+		previous := card.GetColumnID()
+		ids := []int64{}
+		for _, cardID := range s.columns[previous] {
+			if cardID != id {
+				ids = append(ids, cardID)
+			}
+		}
+		s.columns[previous] = ids
+		s.columns[opts.ColumnID] = append(s.columns[opts.ColumnID], id)
+		card.ColumnID = github.Int64(opts.ColumnID)
+	}
+
+	writeJSON(w, http.StatusCreated, struct{}{})
+}
+
+func (s *Server) listCards(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	column, _ := strconv.ParseInt(params[0], 10, 64)
+	items := []interface{}{}
+	for _, id := range s.columns[column] {
+		items = append(items, s.cards[id])
+	}
+	writePage(w, r, items)
+}
+
+func (s *Server) createCard(w http.ResponseWriter, r *http.Request, params []string, body []byte) {
+	opts := github.ProjectCardOptions{}
+	if err := json.Unmarshal(body, &opts); err != nil {
+		badRequest(w, err)
+		return
+	}
+
+	column, _ := strconv.ParseInt(params[0], 10, 64)
+	card := &github.ProjectCard{
+		ID:       github.Int64(s.id()),
+		ColumnID: github.Int64(column),
+	}
+	if opts.Note != "" {
+		card.Note = github.String(opts.Note)
+	}
+	if opts.ContentID != 0 {
+		card.ContentURL = github.String(fmt.Sprintf("%s/%d", opts.ContentType, opts.ContentID))
+	}
+
+	s.cards[card.GetID()] = card
+	s.columns[column] = append(s.columns[column], card.GetID())
+	writeJSON(w, http.StatusCreated, card)
+}
@@ -0,0 +1,219 @@
+package backendtest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+)
+
+func TestServerIssues(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	repo := s.Repo("jedi/temple")
+	repo.Issues = []*github.Issue{
+		{Number: github.Int(1), State: github.String("open"), Title: github.String("younglings")},
+		{Number: github.Int(2), State: github.String("closed"), Title: github.String("order 66")},
+		{Number: github.Int(3), State: github.String("open"), Title: github.String("archives")},
+	}
+
+	c := s.Client()
+	ctx := context.Background()
+
+	tests := []struct {
+		desc   string
+		opts   *github.IssueListByRepoOptions
+		titles []string
+		next   int
+	}{
+		{
+			desc:   "default open state",
+			opts:   &github.IssueListByRepoOptions{},
+			titles: []string{"younglings", "archives"},
+			next:   0,
+		},
+		{
+			desc:   "all states",
+			opts:   &github.IssueListByRepoOptions{State: "all"},
+			titles: []string{"younglings", "order 66", "archives"},
+			next:   0,
+		},
+		{
+			desc: "first page",
+			opts: &github.IssueListByRepoOptions{
+				State:       "all",
+				ListOptions: github.ListOptions{PerPage: 2},
+			},
+			titles: []string{"younglings", "order 66"},
+			next:   2,
+		},
+		{
+			desc: "last page",
+			opts: &github.IssueListByRepoOptions{
+				State:       "all",
+				ListOptions: github.ListOptions{PerPage: 2, Page: 2},
+			},
+			titles: []string{"archives"},
+			next:   0,
+		},
+	}
+
+	for _, test := range tests {
+		issues, resp, err := c.Issues.ListByRepo(ctx, "jedi", "temple", test.opts)
+		if err != nil {
+			t.Errorf("description: %s, error listing issues: %s", test.desc, err.Error())
+			continue
+		}
+
+		titles := []string{}
+		for _, issue := range issues {
+			titles = append(titles, issue.GetTitle())
+		}
+		if !reflect.DeepEqual(titles, test.titles) {
+			t.Errorf("description: %s, titles received: %v, expected: %v", test.desc, titles, test.titles)
+		}
+
+		if resp.NextPage != test.next {
+			t.Errorf("description: %s, next page received: %d, expected: %d", test.desc, resp.NextPage, test.next)
+		}
+	}
+
+	if _, _, err := c.Issues.AddAssignees(ctx, "jedi", "temple", 1, []string{"yoda"}); err != nil {
+		t.Errorf("description: error adding assignees: %s", err.Error())
+	}
+	if login := repo.Issues[0].Assignees[0].GetLogin(); login != "yoda" {
+		t.Errorf("description: assignee received: %s, expected: yoda", login)
+	}
+
+	if _, _, err := c.Issues.AddLabelsToIssue(ctx, "jedi", "temple", 3, []string{"jedi", "sith"}); err != nil {
+		t.Errorf("description: error adding labels: %s", err.Error())
+	}
+	if _, err := c.Issues.RemoveLabelForIssue(ctx, "jedi", "temple", 3, "sith"); err != nil {
+		t.Errorf("description: error removing label: %s", err.Error())
+	}
+	if labels := repo.Issues[2].Labels; len(labels) != 1 || labels[0].GetName() != "jedi" {
+		t.Errorf("description: labels received: %v, expected: [jedi]", labels)
+	}
+
+	if _, _, err := c.Issues.Get(ctx, "jedi", "temple", 66); err == nil {
+		t.Errorf("description: missing issue returned no error")
+	}
+}
+
+func TestServerPullRequests(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	repo := s.Repo("jedi/temple")
+	repo.PullRequests = []*github.PullRequest{
+		{Number: github.Int(4), State: github.String("open")},
+		{Number: github.Int(5), State: github.String("closed")},
+	}
+	repo.Commits[5] = []*github.RepositoryCommit{
+		{SHA: github.String("abc")},
+		{SHA: github.String("def")},
+	}
+	repo.Files[".heupr.yml"] = "backends: []\n"
+
+	c := s.Client()
+	ctx := context.Background()
+
+	prs, _, err := c.PullRequests.List(ctx, "jedi", "temple", &github.PullRequestListOptions{State: "all"})
+	if err != nil || len(prs) != 2 {
+		t.Errorf("description: pull requests received: %v, error: %v", prs, err)
+	}
+
+	commits, _, err := c.PullRequests.ListCommits(ctx, "jedi", "temple", 5, &github.ListOptions{})
+	if err != nil || len(commits) != 2 {
+		t.Errorf("description: commits received: %v, error: %v", commits, err)
+	}
+
+	file, _, _, err := c.Repositories.GetContents(ctx, "jedi", "temple", ".heupr.yml", nil)
+	if err != nil {
+		t.Fatalf("description: error getting contents: %s", err.Error())
+	}
+	if content, _ := file.GetContent(); content != "backends: []\n" {
+		t.Errorf("description: content received: %s, expected: backends: []", content)
+	}
+
+	if _, _, err := c.Issues.CreateComment(ctx, "jedi", "temple", 5, &github.IssueComment{Body: github.String("merged")}); err != nil {
+		t.Errorf("description: error creating comment: %s", err.Error())
+	}
+	if comments := repo.Comments[5]; len(comments) != 1 || comments[0].GetBody() != "merged" {
+		t.Errorf("description: comments received: %v, expected: [merged]", comments)
+	}
+
+	expected := []Request{
+		{
+			Method: "POST",
+			Path:   "/repos/jedi/temple/issues/5/comments",
+			Body:   `{"body":"merged"}`,
+		},
+	}
+	if writes := s.Writes(); !reflect.DeepEqual(writes, expected) {
+		t.Errorf("description: writes received: %+v, expected: %+v", writes, expected)
+	}
+}
+
+func TestServerProjectCards(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.AddCard(10, &github.ProjectCard{ID: github.Int64(1), Note: github.String("train")})
+
+	c := s.Client()
+	ctx := context.Background()
+
+	card, _, err := c.Projects.GetProjectCard(ctx, 1)
+	if err != nil || card.GetNote() != "train" {
+		t.Errorf("description: card received: %v, error: %v", card, err)
+	}
+
+	created, _, err := c.Projects.CreateProjectCard(ctx, 10, &github.ProjectCardOptions{Note: "duel"})
+	if err != nil {
+		t.Fatalf("description: error creating card: %s", err.Error())
+	}
+
+	if _, err := c.Projects.MoveProjectCard(ctx, 1, &github.ProjectCardMoveOptions{Position: "top", ColumnID: 20}); err != nil {
+		t.Errorf("description: error moving card: %s", err.Error())
+	}
+
+	tests := []struct {
+		desc   string
+		column int64
+		notes  []string
+	}{
+		{
+			desc:   "source column",
+			column: 10,
+			notes:  []string{"duel"},
+		},
+		{
+			desc:   "destination column",
+			column: 20,
+			notes:  []string{"train"},
+		},
+	}
+
+	for _, test := range tests {
+		cards, _, err := c.Projects.ListProjectCards(ctx, test.column, nil)
+		if err != nil {
+			t.Errorf("description: %s, error listing cards: %s", test.desc, err.Error())
+			continue
+		}
+
+		notes := []string{}
+		for _, card := range cards {
+			notes = append(notes, card.GetNote())
+		}
+		if !reflect.DeepEqual(notes, test.notes) {
+			t.Errorf("description: %s, notes received: %v, expected: %v", test.desc, notes, test.notes)
+		}
+	}
+
+	if column := s.Card(created.GetID()).GetColumnID(); column != 10 {
+		t.Errorf("description: created card column received: %d, expected: 10", column)
+	}
+}
@@ -1,4 +1,5 @@
-package main
+// Package estimatepr provides a backend for expected vs actual pull request timelines.
+package estimatepr
 
 import (
 	"context"
@@ -41,15 +42,15 @@ to complete based on commit activity.
 */
 
 type helper interface {
-	pullRequests(c *github.Client, owner, repo string) ([]*github.PullRequest, error)
-	commits(c *github.Client, owner, repo string, number int) ([]*github.RepositoryCommit, error)
+	pullRequests(ctx context.Context, c *github.Client, owner, repo string) ([]*github.PullRequest, error)
+	commits(ctx context.Context, c *github.Client, owner, repo string, number int) ([]*github.RepositoryCommit, error)
 	stringPtr(input string) *string
-	comment(c *github.Client, owner, repo string, pr *github.PullRequest) error
+	comment(ctx context.Context, c *github.Client, owner, repo string, pr *github.PullRequest) error
 }
 
 type help struct{}
 
-func (h *help) pullRequests(c *github.Client, owner, repo string) ([]*github.PullRequest, error) {
+func (h *help) pullRequests(ctx context.Context, c *github.Client, owner, repo string) ([]*github.PullRequest, error) {
 	output := []*github.PullRequest{}
 
 	opts := &github.PullRequestListOptions{
@@ -59,7 +60,7 @@ func (h *help) pullRequests(c *github.Client, owner, repo string) ([]*github.Pul
 	}
 
 	for {
-		pullRequests, resp, err := c.PullRequests.List(context.Background(), owner, repo, opts)
+		pullRequests, resp, err := c.PullRequests.List(ctx, owner, repo, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -76,8 +77,8 @@ func (h *help) pullRequests(c *github.Client, owner, repo string) ([]*github.Pul
 	return output, nil
 }
 
-func (h *help) commits(c *github.Client, owner, repo string, number int) ([]*github.RepositoryCommit, error) {
-	commits, _, err := c.PullRequests.ListCommits(context.Background(), owner, repo, number, &github.ListOptions{})
+func (h *help) commits(ctx context.Context, c *github.Client, owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	commits, _, err := c.PullRequests.ListCommits(ctx, owner, repo, number, &github.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -89,8 +90,8 @@ func (h *help) stringPtr(input string) *string {
 	return &input
 }
 
-func (h *help) comment(c *github.Client, owner, repo string, pr *github.PullRequest) error {
-	commits, err := h.commits(c, owner, repo, *pr.Number)
+func (h *help) comment(ctx context.Context, c *github.Client, owner, repo string, pr *github.PullRequest) error {
+	commits, err := h.commits(ctx, c, owner, repo, *pr.Number)
 	if err != nil {
 		return errors.New("error getting commits: " + err.Error())
 	}
@@ -118,7 +119,7 @@ func (h *help) comment(c *github.Client, owner, repo string, pr *github.PullRequ
 		Body: h.stringPtr(fmt.Sprintf("### Completion results\n- Estimated day(s): **%s**\n- Actual day(s): **%d**\n", estimated, actual)),
 	}
 
-	_, _, err = c.Issues.CreateComment(context.Background(), owner, repo, *pr.Number, cmt)
+	_, _, err = c.Issues.CreateComment(ctx, owner, repo, *pr.Number, cmt)
 	if err != nil {
 		return fmt.Errorf("error posting pull request comment: %s", err.Error())
 	}
@@ -126,17 +127,27 @@ func (h *help) comment(c *github.Client, owner, repo string, pr *github.PullRequ
 	return nil
 }
 
-// Backend implements the backend package interface
-var Backend bnkd
+func init() {
+	backend.Register("estimatepr", func() backend.BackendV2 {
+		return &bnkd{}
+	})
+}
 
 type bnkd struct {
 	client *github.Client
 	help   helper
 }
 
+// Manifest declares the event types the backend acts on
+func (b *bnkd) Manifest() backend.Manifest {
+	return backend.Manifest{
+		Events: []string{"pull_request"},
+	}
+}
+
 // Configure configures the backend with a client and helper struct
-func (b *bnkd) Configure(c *github.Client) {
-	log.Println("configure estimate pull request backend")
+func (b *bnkd) Configure(ctx context.Context, r backend.Request, c *github.Client) {
+	r.Printf("configure estimate pull request backend\n")
 	b.client = c
 	b.help = &help{}
 }
@@ -163,47 +174,47 @@ func parseRepos(event string, payloadBytes []byte) ([]*github.Repository, error)
 }
 
 // Prepare processes existing pull requests and calculates points estimates versus actual
-func (b *bnkd) Prepare(p backend.Payload) error {
-	log.Printf("prepare payload bytes: %s\n", string(p.Bytes()))
+func (b *bnkd) Prepare(ctx context.Context, r backend.Request, p backend.Payload) error {
+	r.Printf("prepare payload bytes: %s\n", string(p.Bytes()))
 
 	repos, err := parseRepos(p.Type(), p.Bytes())
 	if err != nil {
 		return errors.New("error unmarshalling installation event: " + err.Error())
 	}
 
-	log.Printf("repositories: %+v\n", repos)
+	r.Printf("repositories: %+v\n", repos)
 	for _, repo := range repos {
-		log.Printf("repository: %s\n", *repo.FullName)
+		r.Printf("repository: %s\n", *repo.FullName)
 		fullName := strings.Split(*repo.FullName, "/")
-		pullRequests, err := b.help.pullRequests(b.client, fullName[0], fullName[1])
+		pullRequests, err := b.help.pullRequests(ctx, b.client, fullName[0], fullName[1])
 		if err != nil {
 			return errors.New("error getting pull requests: " + err.Error())
 		}
 
-		log.Printf("pull requests: %+v\n", pullRequests)
+		r.Printf("pull requests: %+v\n", pullRequests)
 		for _, pr := range pullRequests {
-			log.Printf("pull request: %+v\n", pr)
+			r.Printf("pull request: %+v\n", pr)
 			closed := pr.ClosedAt
 			merged := *pr.Merged
-			log.Printf("closed: %s, merged: %t\n", closed, merged)
+			r.Printf("closed: %s, merged: %t\n", closed, merged)
 			if closed != nil && merged {
-				if err := b.help.comment(b.client, fullName[0], fullName[1], pr); err != nil {
+				if err := b.help.comment(ctx, b.client, fullName[0], fullName[1], pr); err != nil {
 					return errors.New("error posting comment: " + err.Error())
 				}
-				log.Println("posting pull request comment")
+				r.Printf("posting pull request comment\n")
 			}
 		}
 	}
 
-	log.Println("successful prepare invocation")
+	r.Printf("successful prepare invocation\n")
 	return nil
 }
 
 // Act processes new pull requests and calculates points estimates versus actual
-func (b *bnkd) Act(p backend.Payload) error {
-	log.Printf("act payload bytes: %s\n", string(p.Bytes()))
+func (b *bnkd) Act(ctx context.Context, r backend.Request, p backend.Payload) error {
+	r.Printf("act payload bytes: %s\n", string(p.Bytes()))
 	if p.Type() != "pull_request" {
-		log.Printf("type %s not supported for pr estimation\n", p.Type())
+		r.Printf("type %s not supported for pr estimation\n", p.Type())
 		return nil
 	}
 
@@ -215,15 +226,13 @@ func (b *bnkd) Act(p backend.Payload) error {
 	action := *event.Action
 	merged := *event.PullRequest.Merged
 	fullName := strings.Split(*event.Repo.FullName, "/")
-	log.Printf("action: %s, merged: %t, repository: %s\n", action, merged, *event.Repo.FullName)
+	r.Printf("action: %s, merged: %t, repository: %s\n", action, merged, *event.Repo.FullName)
 	if action == "closed" && merged {
-		if err := b.help.comment(b.client, fullName[0], fullName[1], event.PullRequest); err != nil {
+		if err := b.help.comment(ctx, b.client, fullName[0], fullName[1], event.PullRequest); err != nil {
 			return errors.New("error posting comment: " + err.Error())
 		}
 	}
 
-	log.Println("successful act invocation")
+	r.Printf("successful act invocation\n")
 	return nil
 }
-
-func main() {}
@@ -1,6 +1,7 @@
-package main
+package estimatepr
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -13,6 +14,9 @@ import (
 	"time"
 
 	"github.com/google/go-github/v28/github"
+
+	"github.com/heupr/heupr/backend"
+	"github.com/heupr/heupr/backend/backendtest"
 )
 
 func TestMain(m *testing.M) {
@@ -55,7 +59,7 @@ func Test_helpers(t *testing.T) {
 	prs := []*github.PullRequest{}
 	h := &help{}
 	t.Run("test get pull requests", func(t *testing.T) {
-		prs, err = h.pullRequests(c, "kamino", "tipoca")
+		prs, err = h.pullRequests(context.Background(), c, "kamino", "tipoca")
 		if err != nil {
 			t.Errorf("description: bulk pull request retrieval error, received: %s", err.Error())
 		}
@@ -66,7 +70,7 @@ func Test_helpers(t *testing.T) {
 	})
 
 	t.Run("test get commit", func(t *testing.T) {
-		cmts, err := h.commits(c, "kamino", "tipoca", 1)
+		cmts, err := h.commits(context.Background(), c, "kamino", "tipoca", 1)
 		if err != nil {
 			t.Errorf("description: bulk commit retrieval error, received: %s", err.Error())
 		}
@@ -77,22 +81,22 @@ func Test_helpers(t *testing.T) {
 	})
 
 	t.Run("test apply comment single commit", func(t *testing.T) {
-		if err := h.comment(c, "kamino", "tipoca", prs[0]); err != nil {
+		if err := h.comment(context.Background(), c, "kamino", "tipoca", prs[0]); err != nil {
 			t.Errorf("description: pull request comment error, received: %s", err.Error())
 		}
 	})
 
 	t.Run("test apply comment multiple commits", func(t *testing.T) {
-		if err := h.comment(c, "tatooine", "mos-eisley", prs[0]); err != nil {
+		if err := h.comment(context.Background(), c, "tatooine", "mos-eisley", prs[0]); err != nil {
 			t.Errorf("description: pull request comment error, received: %s", err.Error())
 		}
 	})
 }
 
 func TestConfigure(t *testing.T) {
-	b := Backend
+	b := bnkd{}
 	c := github.NewClient(nil)
-	b.Configure(c)
+	b.Configure(context.Background(), backend.Request{}, c)
 
 	if b.client == nil {
 		t.Error("description: error creating backend")
@@ -111,11 +115,11 @@ type mockHelp struct {
 	commentErr         error
 }
 
-func (mock *mockHelp) pullRequests(c *github.Client, owner, repo string) ([]*github.PullRequest, error) {
+func (mock *mockHelp) pullRequests(ctx context.Context, c *github.Client, owner, repo string) ([]*github.PullRequest, error) {
 	return mock.pullRequestsOutput, mock.pullRequestsErr
 }
 
-func (mock *mockHelp) commits(c *github.Client, owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+func (mock *mockHelp) commits(ctx context.Context, c *github.Client, owner, repo string, number int) ([]*github.RepositoryCommit, error) {
 	return mock.commitsOutput, mock.commitsErr
 }
 
@@ -123,7 +127,7 @@ func (mock *mockHelp) stringPtr(input string) *string {
 	return &input
 }
 
-func (mock *mockHelp) comment(c *github.Client, owner, repo string, pr *github.PullRequest) error {
+func (mock *mockHelp) comment(ctx context.Context, c *github.Client, owner, repo string, pr *github.PullRequest) error {
 	return mock.commentErr
 }
 
@@ -140,6 +144,14 @@ func (mock *mockPayload) Bytes() []byte {
 	return []byte(mock.payload)
 }
 
+func (mock *mockPayload) Settings(v interface{}) error {
+	return nil
+}
+
+func (mock *mockPayload) ConfigSource() string {
+	return ""
+}
+
 func (mock *mockPayload) Config() []byte {
 	return []byte("")
 }
@@ -224,10 +236,10 @@ func TestPrepare(t *testing.T) {
 			commentErr:         test.commentErr,
 		}
 
-		b := Backend
+		b := bnkd{}
 		b.help = h
 
-		err := b.Prepare(p)
+		err := b.Prepare(context.Background(), backend.Request{}, p)
 		if err != nil && err.Error() != test.err {
 			t.Errorf("description: %s, error received: %s", test.desc, err.Error())
 		}
@@ -270,16 +282,44 @@ func TestAct(t *testing.T) {
 			commentErr: test.commentErr,
 		}
 
-		b := Backend
+		b := bnkd{}
 		b.help = h
 
-		err := b.Act(p)
+		err := b.Act(context.Background(), backend.Request{}, p)
 		if err != nil && err.Error() != test.err {
 			t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
 		}
 	}
 }
 
-func Test_main(t *testing.T) {
-	main() // invoking for test coverage
+func TestActEndToEnd(t *testing.T) {
+	s := backendtest.NewServer()
+	defer s.Close()
+
+	start := time.Date(2019, time.May, 4, 0, 0, 0, 0, time.UTC)
+	pr := &github.PullRequest{
+		Number: github.Int(66),
+		Merged: github.Bool(true),
+		Labels: []*github.Label{{Name: github.String("est-2")}},
+	}
+
+	repo := s.Repo("kamino/tipoca")
+	repo.PullRequests = []*github.PullRequest{pr}
+	repo.Commits[66] = []*github.RepositoryCommit{
+		{Commit: &github.Commit{Author: &github.CommitAuthor{Date: &start}}},
+		{Commit: &github.Commit{Author: &github.CommitAuthor{Date: timePtr(start.Add(72 * time.Hour))}}},
+	}
+
+	r := backendtest.NewRequest("estimatepr", "pull_request", "kamino/tipoca")
+	b := &bnkd{}
+	b.Configure(context.Background(), r, s.Client())
+
+	if err := b.Act(context.Background(), r, backendtest.PullRequestPayload("closed", "kamino/tipoca", pr)); err != nil {
+		t.Fatalf("description: unexpected act error: %s", err.Error())
+	}
+
+	expected := "### Completion results\n- Estimated day(s): **2**\n- Actual day(s): **4**\n"
+	if comments := repo.Comments[66]; len(comments) != 1 || comments[0].GetBody() != expected {
+		t.Errorf("description: comments received: %v, expected: %s", comments, expected)
+	}
 }
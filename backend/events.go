@@ -0,0 +1,64 @@
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v28/github"
+)
+
+// decode unmarshals the payload bytes after checking the payload type
+func decode(p Payload, eventType string, event interface{}) error {
+	if p.Type() != eventType {
+		return fmt.Errorf("payload type %s is not %s", p.Type(), eventType)
+	}
+
+	if err := json.Unmarshal(p.Bytes(), event); err != nil {
+		return fmt.Errorf("error parsing %s payload: %s", eventType, err.Error())
+	}
+
+	return nil
+}
+
+// IssueComment decodes an issue_comment event payload
+func IssueComment(p Payload) (*github.IssueCommentEvent, error) {
+	event := &github.IssueCommentEvent{}
+	if err := decode(p, "issue_comment", event); err != nil {
+		return nil, err
+	}
+
+	if event.Repo == nil || event.Issue == nil || event.Comment == nil {
+		return nil, errors.New("issue_comment payload missing repository, issue, or comment")
+	}
+
+	return event, nil
+}
+
+// PullRequestReview decodes a pull_request_review event payload
+func PullRequestReview(p Payload) (*github.PullRequestReviewEvent, error) {
+	event := &github.PullRequestReviewEvent{}
+	if err := decode(p, "pull_request_review", event); err != nil {
+		return nil, err
+	}
+
+	if event.Repo == nil || event.PullRequest == nil || event.Review == nil {
+		return nil, errors.New("pull_request_review payload missing repository, pull request, or review")
+	}
+
+	return event, nil
+}
+
+// PullRequestReviewComment decodes a pull_request_review_comment event payload
+func PullRequestReviewComment(p Payload) (*github.PullRequestReviewCommentEvent, error) {
+	event := &github.PullRequestReviewCommentEvent{}
+	if err := decode(p, "pull_request_review_comment", event); err != nil {
+		return nil, err
+	}
+
+	if event.Repo == nil || event.PullRequest == nil || event.Comment == nil {
+		return nil, errors.New("pull_request_review_comment payload missing repository, pull request, or comment")
+	}
+
+	return event, nil
+}
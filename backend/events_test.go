@@ -0,0 +1,152 @@
+package backend
+
+import (
+	"testing"
+)
+
+type testPayload struct {
+	payloadType  string
+	payloadBytes string
+}
+
+func (tp *testPayload) Type() string {
+	return tp.payloadType
+}
+
+func (tp *testPayload) Bytes() []byte {
+	return []byte(tp.payloadBytes)
+}
+
+func (tp *testPayload) Config() []byte {
+	return nil
+}
+
+func (tp *testPayload) Settings(v interface{}) error {
+	return nil
+}
+
+func (tp *testPayload) ConfigSource() string {
+	return ""
+}
+
+func TestIssueComment(t *testing.T) {
+	tests := []struct {
+		desc    string
+		payload *testPayload
+		body    string
+		err     string
+	}{
+		{
+			desc:    "incorrect payload type",
+			payload: &testPayload{payloadType: "issues", payloadBytes: `{}`},
+			err:     "payload type issues is not issue_comment",
+		},
+		{
+			desc:    "invalid payload bytes",
+			payload: &testPayload{payloadType: "issue_comment", payloadBytes: `{`},
+			err:     "error parsing issue_comment payload: unexpected end of JSON input",
+		},
+		{
+			desc:    "missing comment",
+			payload: &testPayload{payloadType: "issue_comment", payloadBytes: `{"repository":{"full_name":"jedi/archives"},"issue":{"number":66}}`},
+			err:     "issue_comment payload missing repository, issue, or comment",
+		},
+		{
+			desc:    "successful invocation",
+			payload: &testPayload{payloadType: "issue_comment", payloadBytes: `{"action":"created","repository":{"full_name":"jedi/archives"},"issue":{"number":66},"comment":{"body":"execute order 66"}}`},
+			body:    "execute order 66",
+		},
+	}
+
+	for _, test := range tests {
+		event, err := IssueComment(test.payload)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
+			}
+			continue
+		}
+
+		if event.GetComment().GetBody() != test.body {
+			t.Errorf("description: %s, comment received: %s, expected: %s", test.desc, event.GetComment().GetBody(), test.body)
+		}
+	}
+}
+
+func TestPullRequestReview(t *testing.T) {
+	tests := []struct {
+		desc    string
+		payload *testPayload
+		state   string
+		err     string
+	}{
+		{
+			desc:    "incorrect payload type",
+			payload: &testPayload{payloadType: "pull_request", payloadBytes: `{}`},
+			err:     "payload type pull_request is not pull_request_review",
+		},
+		{
+			desc:    "missing review",
+			payload: &testPayload{payloadType: "pull_request_review", payloadBytes: `{"repository":{"full_name":"jedi/archives"},"pull_request":{"number":66}}`},
+			err:     "pull_request_review payload missing repository, pull request, or review",
+		},
+		{
+			desc:    "successful invocation",
+			payload: &testPayload{payloadType: "pull_request_review", payloadBytes: `{"action":"submitted","repository":{"full_name":"jedi/archives"},"pull_request":{"number":66},"review":{"state":"approved"}}`},
+			state:   "approved",
+		},
+	}
+
+	for _, test := range tests {
+		event, err := PullRequestReview(test.payload)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
+			}
+			continue
+		}
+
+		if event.GetReview().GetState() != test.state {
+			t.Errorf("description: %s, state received: %s, expected: %s", test.desc, event.GetReview().GetState(), test.state)
+		}
+	}
+}
+
+func TestPullRequestReviewComment(t *testing.T) {
+	tests := []struct {
+		desc    string
+		payload *testPayload
+		body    string
+		err     string
+	}{
+		{
+			desc:    "incorrect payload type",
+			payload: &testPayload{payloadType: "issue_comment", payloadBytes: `{}`},
+			err:     "payload type issue_comment is not pull_request_review_comment",
+		},
+		{
+			desc:    "missing pull request",
+			payload: &testPayload{payloadType: "pull_request_review_comment", payloadBytes: `{"repository":{"full_name":"jedi/archives"},"comment":{"body":"not to worry"}}`},
+			err:     "pull_request_review_comment payload missing repository, pull request, or comment",
+		},
+		{
+			desc:    "successful invocation",
+			payload: &testPayload{payloadType: "pull_request_review_comment", payloadBytes: `{"action":"created","repository":{"full_name":"jedi/archives"},"pull_request":{"number":66},"comment":{"body":"we are still flying half a ship"}}`},
+			body:    "we are still flying half a ship",
+		},
+	}
+
+	for _, test := range tests {
+		event, err := PullRequestReviewComment(test.payload)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
+			}
+			continue
+		}
+
+		if event.GetComment().GetBody() != test.body {
+			t.Errorf("description: %s, comment received: %s, expected: %s", test.desc, event.GetComment().GetBody(), test.body)
+		}
+	}
+}
@@ -1,4 +1,5 @@
-package main
+// Package projectboard provides a backend for Project Board update messages.
+package projectboard
 
 import (
 	"context"
@@ -9,7 +10,6 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/go-yaml/yaml"
 	"github.com/google/go-github/v28/github"
 	"github.com/tidwall/gjson"
 
@@ -39,7 +39,7 @@ DO NOT include sensative URLs in this config (e.g. Slack URLs).
 
 type helper interface {
 	postHTTP(url string, body io.Reader) error
-	parseMessage(eventType, payloadString string) string
+	parseMessage(ctx context.Context, eventType, payloadString string) string
 }
 
 type help struct {
@@ -59,7 +59,7 @@ func (h *help) postHTTP(url string, body io.Reader) error {
 	return nil
 }
 
-func (h *help) parseMessage(eventType, payloadString string) string {
+func (h *help) parseMessage(ctx context.Context, eventType, payloadString string) string {
 	message := ""
 
 	user := gjson.Get(payloadString, "sender.login").String()
@@ -76,7 +76,7 @@ func (h *help) parseMessage(eventType, payloadString string) string {
 		projectCardID := gjson.Get(payloadString, "project_card.id").Int()
 		log.Printf("project card ID: %d", projectCardID)
 
-		card, _, err := h.client.Projects.GetProjectCard(context.Background(), projectCardID)
+		card, _, err := h.client.Projects.GetProjectCard(ctx, projectCardID)
 		if err != nil {
 			return message
 		}
@@ -84,7 +84,7 @@ func (h *help) parseMessage(eventType, payloadString string) string {
 
 		if action == "moved" && (card.PreviousColumnName == nil || card.ColumnName == nil) {
 			message = fmt.Sprintf("user %s %s card", user, action)
-		} else if action == "moved" && (card.PreviousColumnName == nil || card.ColumnName == nil) {
+		} else if action == "moved" {
 			message = fmt.Sprintf("user %s %s %d from %s to %s", user, action, projectCardID, *card.PreviousColumnName, *card.ColumnName)
 		} else {
 			message = fmt.Sprintf("user %s %s project card %d", user, action, projectCardID)
@@ -99,24 +99,34 @@ func (h *help) parseMessage(eventType, payloadString string) string {
 	return message
 }
 
-// Backend implements the backend package interface
-var Backend bnkd
+func init() {
+	backend.Register("projectboard", func() backend.BackendV2 {
+		return &bnkd{}
+	})
+}
 
 type bnkd struct {
 	help helper
 }
 
 // Configure configures the backend with a client
-func (b *bnkd) Configure(c *github.Client) {
-	log.Println("configure project board backend")
+func (b *bnkd) Configure(ctx context.Context, r backend.Request, c *github.Client) {
+	r.Printf("configure project board backend\n")
 	b.help = &help{
 		client: c,
 	}
 }
 
+// Manifest declares the event types the backend acts on
+func (b *bnkd) Manifest() backend.Manifest {
+	return backend.Manifest{
+		Events: []string{"project", "project_card", "project_column"},
+	}
+}
+
 // Prepare processes performs no action but implements the Backend interface
-func (b *bnkd) Prepare(p backend.Payload) error {
-	log.Printf("prepare payload bytes: %s\n", string(p.Bytes()))
+func (b *bnkd) Prepare(ctx context.Context, r backend.Request, p backend.Payload) error {
+	r.Printf("prepare payload bytes: %s\n", string(p.Bytes()))
 
 	return nil
 }
@@ -125,40 +135,29 @@ type settings struct {
 	URLs []string `yaml:"urls"`
 }
 
-type backendObj struct {
-	Name     string   `yaml:"name"`
-	Settings settings `yaml:"settings"`
-}
-
-type configObj struct {
-	Backends []backendObj `yaml:"backends"`
+// Settings returns the type the backend settings block is validated against
+func (b *bnkd) Settings() interface{} {
+	return &settings{}
 }
 
 // Act processes Project Board actions and posts messages to the configured URL
-func (b *bnkd) Act(p backend.Payload) error {
-	log.Printf("act payload bytes: %s\n", string(p.Bytes()))
+func (b *bnkd) Act(ctx context.Context, r backend.Request, p backend.Payload) error {
+	r.Printf("act payload bytes: %s\n", string(p.Bytes()))
 
 	payloadString := string(p.Bytes())
-	message := b.help.parseMessage(p.Type(), payloadString)
+	message := b.help.parseMessage(ctx, p.Type(), payloadString)
 	if message == "" {
 		return errors.New("no output message")
 	}
 
 	output := fmt.Sprintf(`{"message": %s}`, message)
 
-	config := configObj{}
-	if err := yaml.Unmarshal(p.Config(), &config); err != nil {
-		return fmt.Errorf("error parsing heupr config: %s", err.Error())
+	s := settings{}
+	if err := p.Settings(&s); err != nil {
+		return fmt.Errorf("error parsing settings: %s", err.Error())
 	}
 
-	urls := []string{}
-	for _, bknd := range config.Backends {
-		if bknd.Name == "projectboard" {
-			urls = bknd.Settings.URLs
-		}
-	}
-
-	for _, url := range urls {
+	for _, url := range s.URLs {
 		if err := b.help.postHTTP(url, strings.NewReader(output)); err != nil {
 			return fmt.Errorf("error posting target url: %s", err.Error())
 		}
@@ -166,5 +165,3 @@ func (b *bnkd) Act(p backend.Payload) error {
 
 	return nil
 }
-
-func main() {}
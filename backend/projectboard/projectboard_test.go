@@ -1,6 +1,7 @@
-package main
+package projectboard
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,8 +13,10 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/go-yaml/yaml"
 	"github.com/google/go-github/v28/github"
+	"gopkg.in/yaml.v3"
+
+	"github.com/heupr/heupr/backend"
 )
 
 func TestMain(m *testing.M) {
@@ -123,7 +126,7 @@ func Test_parseMessage(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		msg := h.parseMessage(test.eventType, test.eventPayload)
+		msg := h.parseMessage(context.Background(), test.eventType, test.eventPayload)
 		if msg != test.msg {
 			t.Errorf("description: %s, message received: %s, expected: %s", test.desc, msg, test.msg)
 		}
@@ -131,9 +134,10 @@ func Test_parseMessage(t *testing.T) {
 }
 
 type mockPayload struct {
-	payloadBytes  string
-	payloadType   string
-	payloadConfig []byte
+	payloadBytes    string
+	payloadType     string
+	payloadConfig   []byte
+	payloadSettings []byte
 }
 
 func (mock *mockPayload) Type() string {
@@ -148,6 +152,14 @@ func (mock *mockPayload) Config() []byte {
 	return mock.payloadConfig
 }
 
+func (mock *mockPayload) Settings(v interface{}) error {
+	return yaml.Unmarshal(mock.payloadSettings, v)
+}
+
+func (mock *mockPayload) ConfigSource() string {
+	return ""
+}
+
 type mockHelp struct {
 	postHTTPErr        error
 	parseMessageOutput string
@@ -157,14 +169,14 @@ func (m *mockHelp) postHTTP(url string, body io.Reader) error {
 	return m.postHTTPErr
 }
 
-func (m *mockHelp) parseMessage(eventType, payloadString string) string {
+func (m *mockHelp) parseMessage(ctx context.Context, eventType, payloadString string) string {
 	return m.parseMessageOutput
 }
 
 func TestPrepare(t *testing.T) {
 	p := &mockPayload{}
-	b := Backend
-	if err := b.Prepare(p); err != nil {
+	b := bnkd{}
+	if err := b.Prepare(context.Background(), backend.Request{}, p); err != nil {
 		t.Errorf("description: error calling prepare, error: %s", err.Error())
 	}
 }
@@ -213,28 +225,21 @@ func TestAct(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		c := configObj{
-			Backends: []backendObj{
-				backendObj{
-					Name: "projectboard",
-					Settings: settings{
-						URLs: []string{
-							"https://test.com",
-						},
-					},
-				},
+		s := settings{
+			URLs: []string{
+				"https://test.com",
 			},
 		}
 
-		config, err := yaml.Marshal(&c)
+		content, err := yaml.Marshal(&s)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		p := &mockPayload{
-			payloadBytes:  test.payloadBytes,
-			payloadType:   test.payloadType,
-			payloadConfig: config,
+			payloadBytes:    test.payloadBytes,
+			payloadType:     test.payloadType,
+			payloadSettings: content,
 		}
 
 		h := &mockHelp{
@@ -242,10 +247,10 @@ func TestAct(t *testing.T) {
 			parseMessageOutput: test.parseMessageOutput,
 		}
 
-		b := Backend
+		b := bnkd{}
 		b.help = h
 
-		err = b.Act(p)
+		err = b.Act(context.Background(), backend.Request{}, p)
 		if err != nil && err.Error() != test.err {
 			t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
 		}
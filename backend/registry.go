@@ -0,0 +1,245 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"plugin"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v28/github"
+)
+
+// Factory creates a new instance of a BackendV2 implementation
+type Factory func() BackendV2
+
+var (
+	factoriesMu sync.Mutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a backend compiled into the binary available by name and panics on duplicates
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("backend: register factory is nil for " + name)
+	}
+
+	if _, ok := factories[name]; ok {
+		panic("backend: register called twice for " + name)
+	}
+
+	factories[name] = factory
+}
+
+// Registry holds the backend factories available to the application keyed by name
+type Registry struct {
+	factories map[string]Factory
+	failures  map[string]error
+	manifests map[string]Manifest
+	settings  map[string]interface{}
+}
+
+// NewRegistry creates a Registry instance containing the built-in backends
+func NewRegistry() *Registry {
+	r := &Registry{
+		factories: make(map[string]Factory),
+		failures:  make(map[string]error),
+		manifests: make(map[string]Manifest),
+		settings:  make(map[string]interface{}),
+	}
+
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	for name, factory := range factories {
+		r.add(name, factory)
+	}
+
+	return r
+}
+
+// add stores the factory along with the manifest and settings type of one instance
+func (r *Registry) add(name string, factory Factory) {
+	bknd := factory()
+	r.factories[name] = factory
+	r.manifests[name] = manifest(bknd)
+	r.settings[name] = settings(bknd)
+	delete(r.failures, name)
+}
+
+// Add makes a backend available by name, listing it as unavailable if the factory is nil
+func (r *Registry) Add(name string, factory Factory) {
+	if factory == nil {
+		delete(r.factories, name)
+		delete(r.manifests, name)
+		delete(r.settings, name)
+		r.failures[name] = errors.New("backend factory is nil")
+		return
+	}
+
+	r.add(name, factory)
+}
+
+var openPlugin = func(path string) (Factory, error) {
+	plug, err := plugin.Open(path)
+	if err != nil {
+		return nil, errors.New("error opening plugin file: " + err.Error())
+	}
+
+	if symNew, err := plug.Lookup("New"); err == nil {
+		switch newBackend := symNew.(type) {
+		case func() BackendV2:
+			return newBackend, nil
+		case func() Backend:
+			return func() BackendV2 {
+				return Adapt(newBackend())
+			}, nil
+		}
+
+		return nil, errors.New("error asserting backend plugin constructor type")
+	}
+
+	symBackend, err := plug.Lookup("Backend")
+	if err != nil {
+		return nil, errors.New("error looking up backend plugin: " + err.Error())
+	}
+
+	switch bknd := symBackend.(type) {
+	case BackendV2:
+		return shared(bknd), nil
+	case Backend:
+		return shared(Adapt(bknd)), nil
+	}
+
+	return nil, errors.New("error asserting backend plugin type")
+}
+
+// shared creates a factory serializing invocations of plugins exporting only a single Backend value
+func shared(bknd BackendV2) Factory {
+	mu := &sync.Mutex{}
+	return func() BackendV2 {
+		return &serialized{
+			mu:      mu,
+			backend: bknd,
+		}
+	}
+}
+
+type serialized struct {
+	mu      *sync.Mutex
+	backend BackendV2
+	client  *github.Client
+}
+
+func (s *serialized) Configure(ctx context.Context, r Request, c *github.Client) {
+	s.client = c
+}
+
+func (s *serialized) Prepare(ctx context.Context, r Request, p Payload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.backend.Configure(ctx, r, s.client)
+	return s.backend.Prepare(ctx, r, p)
+}
+
+func (s *serialized) Act(ctx context.Context, r Request, p Payload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.backend.Configure(ctx, r, s.client)
+	return s.backend.Act(ctx, r, p)
+}
+
+// Manifest forwards the manifest of the shared backend
+func (s *serialized) Manifest() Manifest {
+	return manifest(s.backend)
+}
+
+// Settings forwards the settings type of the shared backend
+func (s *serialized) Settings() interface{} {
+	return settings(s.backend)
+}
+
+// Load opens the backend plugin files in the provided directory, recording any that fail in Failures
+func (r *Registry) Load(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return errors.New("error reading plugin files: " + err.Error())
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".so" {
+			continue
+		}
+
+		name := strings.TrimSuffix(file.Name(), ".so")
+		if _, ok := r.factories[name]; ok {
+			r.failures[name] = errors.New("backend name already registered")
+			continue
+		}
+
+		factory, err := openPlugin(filepath.Join(dir, file.Name()))
+		if err != nil {
+			r.failures[name] = err
+			continue
+		}
+
+		r.add(name, factory)
+	}
+
+	return nil
+}
+
+// Factories returns the backend factories keyed by name, with nil factories for plugins that failed to load
+func (r *Registry) Factories() map[string]Factory {
+	output := make(map[string]Factory, len(r.factories)+len(r.failures))
+	for name := range r.failures {
+		output[name] = nil
+	}
+	for name, factory := range r.factories {
+		output[name] = factory
+	}
+	return output
+}
+
+// Factory returns the named backend factory or nil if it is not available
+func (r *Registry) Factory(name string) Factory {
+	return r.factories[name]
+}
+
+// Manifest returns the manifest of the named backend and whether it is available
+func (r *Registry) Manifest(name string) (Manifest, bool) {
+	m, ok := r.manifests[name]
+	return m, ok
+}
+
+// Settings returns the settings type of the named backend or nil if it accepts no settings block
+func (r *Registry) Settings(name string) interface{} {
+	return r.settings[name]
+}
+
+// Names returns the sorted names of the successfully loaded backends
+func (r *Registry) Names() []string {
+	names := []string{}
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Failures returns the errors for any backends that failed to load keyed by name
+func (r *Registry) Failures() map[string]error {
+	output := make(map[string]error, len(r.failures))
+	for name, err := range r.failures {
+		output[name] = err
+	}
+	return output
+}
@@ -0,0 +1,184 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+)
+
+type testBackend struct {
+	configured bool
+	prepareErr error
+	actErr     error
+}
+
+func (tb *testBackend) Configure(*github.Client) {
+	tb.configured = true
+}
+
+func (tb *testBackend) Prepare(Payload) error {
+	return tb.prepareErr
+}
+
+func (tb *testBackend) Act(Payload) error {
+	return tb.actErr
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"assignissue.so", "projectboard.so", "README.md"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer func(open func(string) (Factory, error)) {
+		openPlugin = open
+	}(openPlugin)
+
+	openPlugin = func(path string) (Factory, error) {
+		if filepath.Base(path) == "projectboard.so" {
+			return nil, errors.New("mock open error")
+		}
+		return func() BackendV2 {
+			return Adapt(&testBackend{})
+		}, nil
+	}
+
+	r := NewRegistry()
+	if err := r.Load(dir); err != nil {
+		t.Fatalf("description: error loading plugins, error: %s", err.Error())
+	}
+
+	if _, ok := r.Factories()["assignissue"]; !ok {
+		t.Errorf("description: backend not loaded, received: %v", r.Names())
+	}
+
+	if factory, ok := r.Factories()["projectboard"]; !ok || factory != nil {
+		t.Errorf("description: failed backend not listed as unavailable, received: %v", r.Factories())
+	}
+
+	err = r.Failures()["projectboard"]
+	if err == nil || err.Error() != "mock open error" {
+		t.Errorf("description: failure not recorded, received: %v", r.Failures())
+	}
+
+	if err := r.Load(filepath.Join(dir, "missing")); err == nil {
+		t.Error("description: no error reading missing directory")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer func() {
+		factoriesMu.Lock()
+		delete(factories, "test-builtin")
+		factoriesMu.Unlock()
+	}()
+
+	Register("test-builtin", func() BackendV2 {
+		return Adapt(&testBackend{})
+	})
+
+	r := NewRegistry()
+	if _, ok := r.Factories()["test-builtin"]; !ok {
+		t.Errorf("description: built-in backend not registered, received: %v", r.Names())
+	}
+
+	dir, err := ioutil.TempDir("", "plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "test-builtin.so"), []byte{}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Load(dir); err != nil {
+		t.Fatalf("description: error loading plugins, error: %s", err.Error())
+	}
+
+	if err := r.Failures()["test-builtin"]; err == nil {
+		t.Error("description: plugin name collision not recorded")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("description: duplicate registration did not panic")
+		}
+	}()
+
+	Register("test-builtin", func() BackendV2 {
+		return Adapt(&testBackend{})
+	})
+}
+
+func TestRegistryAdd(t *testing.T) {
+	calls := 0
+	r := NewRegistry()
+	r.Add("test-manifest", func() BackendV2 {
+		calls++
+		return Adapt(&declaredBackend{})
+	})
+	r.Add("test-default", func() BackendV2 {
+		return Adapt(&testBackend{})
+	})
+	r.Add("test-failed", nil)
+
+	for i := 0; i < 2; i++ {
+		if m, ok := r.Manifest("test-manifest"); !ok || !m.Handles("push") {
+			t.Errorf("description: manifest not recorded, received: %+v", m)
+		}
+		if r.Settings("test-manifest") == nil {
+			t.Error("description: settings type not recorded")
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("description: factory calls received: %d, expected: 1", calls)
+	}
+
+	if m, _ := r.Manifest("test-default"); !m.Handles("issues") || r.Settings("test-default") != nil {
+		t.Errorf("description: default manifest or settings incorrect, received: %+v", m)
+	}
+
+	if _, ok := r.Manifest("test-failed"); ok || r.Factory("test-failed") != nil || r.Failures()["test-failed"] == nil {
+		t.Errorf("description: nil factory not listed as failed, received: %v", r.Failures())
+	}
+}
+
+func Test_shared(t *testing.T) {
+	tb := &testBackend{
+		actErr: errors.New("mock act error"),
+	}
+
+	factory := shared(Adapt(tb))
+	first, second := factory(), factory()
+	if first == second {
+		t.Error("description: shared factory returned the same instance")
+	}
+
+	ctx := context.Background()
+	first.Configure(ctx, Request{}, github.NewClient(nil))
+	if tb.configured {
+		t.Error("description: shared backend configured before invocation")
+	}
+
+	if err := first.Act(ctx, Request{}, nil); err == nil || err.Error() != "mock act error" {
+		t.Errorf("description: incorrect act error, received: %v", err)
+	}
+
+	if !tb.configured {
+		t.Error("description: shared backend not configured on invocation")
+	}
+}
@@ -0,0 +1,243 @@
+// Package config defines and validates the .heupr.yml repository configuration file.
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Event defines a webhook event, and optionally its actions, a backend receives
+type Event struct {
+	Name    string   `yaml:"name"`
+	Actions []string `yaml:"actions,omitempty"`
+
+	line int
+}
+
+// UnmarshalYAML records the line the event is defined on
+func (e *Event) UnmarshalYAML(value *yaml.Node) error {
+	type plain Event
+	if err := value.Decode((*plain)(e)); err != nil {
+		return err
+	}
+
+	e.line = value.Line
+	return nil
+}
+
+// Backend defines the configuration block for a single backend
+type Backend struct {
+	Name     string    `yaml:"name"`
+	Events   []Event   `yaml:"events,omitempty"`
+	Settings yaml.Node `yaml:"settings,omitempty"`
+	Location string    `yaml:"location,omitempty"`
+	Merge    string    `yaml:"merge,omitempty"`
+
+	line int
+}
+
+// UnmarshalYAML records the line the backend is defined on
+func (b *Backend) UnmarshalYAML(value *yaml.Node) error {
+	type plain Backend
+	if err := value.Decode((*plain)(b)); err != nil {
+		return err
+	}
+
+	b.line = value.Line
+	return nil
+}
+
+// Line returns the line number of the backend block in the config file
+func (b Backend) Line() int {
+	return b.line
+}
+
+// DryRun configures recording the GitHub changes backends would make instead of making them
+type DryRun struct {
+	Enabled bool `yaml:"enabled"`
+	Comment bool `yaml:"comment,omitempty"`
+}
+
+// Config defines the schema of the .heupr.yml file
+type Config struct {
+	Backends []Backend `yaml:"backends"`
+	DryRun   *DryRun   `yaml:"dry_run,omitempty"`
+}
+
+// Error describes a problem found on a line of the config file
+type Error struct {
+	Line    int
+	Message string
+}
+
+// Error formats the message with its line number
+func (e Error) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Errors aggregates every problem found in the config file
+type Errors []Error
+
+// Error joins the messages of every error
+func (e Errors) Error() string {
+	msgs := []string{}
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+var lineRegexp = regexp.MustCompile(`line (\d+): (.*)`)
+
+// yamlErrors converts errors returned by the yaml package into Errors
+func yamlErrors(err error) Errors {
+	msgs := []string{}
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		msgs = typeErr.Errors
+	} else {
+		msgs = append(msgs, strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+
+	output := Errors{}
+	for _, msg := range msgs {
+		match := lineRegexp.FindStringSubmatch(msg)
+		if match == nil {
+			output = append(output, Error{Message: msg})
+			continue
+		}
+
+		line, _ := strconv.Atoi(match[1])
+		output = append(output, Error{
+			Line:    line,
+			Message: match[2],
+		})
+	}
+
+	return output
+}
+
+// Parse decodes the content of a .heupr.yml file
+func Parse(content []byte) (*Config, error) {
+	config := &Config{}
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return nil, yamlErrors(err)
+	}
+
+	return config, nil
+}
+
+// Events lists the GitHub webhook event names which may be referenced in any config file
+var Events = map[string]bool{
+	"check_run":                   true,
+	"check_suite":                 true,
+	"commit_comment":              true,
+	"create":                      true,
+	"delete":                      true,
+	"deployment":                  true,
+	"deployment_status":           true,
+	"fork":                        true,
+	"gollum":                      true,
+	"issue_comment":               true,
+	"issues":                      true,
+	"label":                       true,
+	"member":                      true,
+	"milestone":                   true,
+	"project":                     true,
+	"project_card":                true,
+	"project_column":              true,
+	"public":                      true,
+	"pull_request":                true,
+	"pull_request_review":         true,
+	"pull_request_review_comment": true,
+	"push":                        true,
+	"release":                     true,
+	"repository":                  true,
+	"status":                      true,
+	"watch":                       true,
+}
+
+// Validate checks the config against the settings types and declared events of the available backends
+func (c *Config) Validate(schemas map[string]interface{}, events map[string]bool) error {
+	errs := Errors{}
+
+	seen := make(map[string]bool)
+	for _, bknd := range c.Backends {
+		if bknd.Name == "" {
+			errs = append(errs, Error{Line: bknd.line, Message: "backend name is required"})
+			continue
+		}
+
+		schema, ok := schemas[bknd.Name]
+		if !ok {
+			errs = append(errs, Error{Line: bknd.line, Message: fmt.Sprintf("unknown backend %q", bknd.Name)})
+			continue
+		}
+
+		if seen[bknd.Name] {
+			errs = append(errs, Error{Line: bknd.line, Message: fmt.Sprintf("backend %q listed more than once", bknd.Name)})
+		}
+		seen[bknd.Name] = true
+
+		if !Merges[bknd.Merge] {
+			errs = append(errs, Error{Line: bknd.line, Message: fmt.Sprintf("unknown merge strategy %q for backend %q", bknd.Merge, bknd.Name)})
+		}
+
+		for _, event := range bknd.Events {
+			if !Events[event.Name] && !events[event.Name] {
+				errs = append(errs, Error{Line: event.line, Message: fmt.Sprintf("unknown event %q for backend %q", event.Name, bknd.Name)})
+			}
+		}
+
+		if schema != nil && bknd.Settings.Kind != 0 {
+			settings := reflect.New(reflect.TypeOf(schema).Elem()).Interface()
+			if err := bknd.Settings.Decode(settings); err != nil {
+				for _, settingsErr := range yamlErrors(err) {
+					settingsErr.Message = fmt.Sprintf("invalid settings for backend %q: %s", bknd.Name, settingsErr.Message)
+					errs = append(errs, settingsErr)
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool {
+			return errs[i].Line < errs[j].Line
+		})
+		return errs
+	}
+
+	return nil
+}
+
+// Backend returns the configuration block for the named backend
+func (c *Config) Backend(name string) (Backend, bool) {
+	for _, bknd := range c.Backends {
+		if bknd.Name == name {
+			return bknd, true
+		}
+	}
+
+	return Backend{}, false
+}
+
+// Decode decodes the backend settings block, if any, into the provided value
+func (b Backend) Decode(v interface{}) error {
+	if b.Settings.Kind == 0 {
+		return nil
+	}
+
+	if err := b.Settings.Decode(v); err != nil {
+		return yamlErrors(err)
+	}
+
+	return nil
+}
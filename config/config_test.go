@@ -0,0 +1,172 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testSettings struct {
+	URLs []string `yaml:"urls"`
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		desc    string
+		content string
+		names   []string
+		err     string
+	}{
+		{
+			desc:    "invalid yaml syntax",
+			content: "backends:\n- name: [",
+			names:   nil,
+			err:     "line 2: did not find expected node content",
+		},
+		{
+			desc:    "incorrect backends type",
+			content: "backends: test",
+			names:   nil,
+			err:     "line 1: cannot unmarshal !!str `test` into []config.Backend",
+		},
+		{
+			desc:    "successful parse",
+			content: "backends:\n- name: first\n- name: second",
+			names:   []string{"first", "second"},
+			err:     "",
+		},
+	}
+
+	for _, test := range tests {
+		cfg, err := Parse([]byte(test.content))
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
+			}
+			continue
+		}
+
+		names := []string{}
+		for _, bknd := range cfg.Backends {
+			names = append(names, bknd.Name)
+		}
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("description: %s, names received: %v, expected: %v", test.desc, names, test.names)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schemas := map[string]interface{}{
+		"settings":   &testSettings{},
+		"nosettings": nil,
+	}
+
+	tests := []struct {
+		desc    string
+		content string
+		err     string
+	}{
+		{
+			desc:    "missing backend name",
+			content: "backends:\n- events:\n  - name: issues",
+			err:     "line 2: backend name is required",
+		},
+		{
+			desc:    "unknown backend",
+			content: "backends:\n- name: nosettings\n- name: missing",
+			err:     `line 3: unknown backend "missing"`,
+		},
+		{
+			desc:    "duplicate backend",
+			content: "backends:\n- name: nosettings\n- name: nosettings",
+			err:     `line 3: backend "nosettings" listed more than once`,
+		},
+		{
+			desc:    "unknown event name",
+			content: "backends:\n- name: nosettings\n  events:\n  - name: issues\n  - name: issue",
+			err:     `line 5: unknown event "issue" for backend "nosettings"`,
+		},
+		{
+			desc:    "event declared by a backend",
+			content: "backends:\n- name: nosettings\n  events:\n  - name: deploy_review",
+			err:     "",
+		},
+		{
+			desc:    "unknown merge strategy",
+			content: "backends:\n- name: nosettings\n  merge: replace",
+			err:     `line 2: unknown merge strategy "replace" for backend "nosettings"`,
+		},
+		{
+			desc:    "invalid settings type",
+			content: "backends:\n- name: settings\n  settings:\n    urls: https://test.com",
+			err:     `line 4: invalid settings for backend "settings": cannot unmarshal !!str ` + "`https:/...`" + ` into []string`,
+		},
+		{
+			desc:    "multiple errors sorted by line",
+			content: "backends:\n- name: missing\n- name: nosettings\n  events:\n  - name: issue",
+			err:     `line 2: unknown backend "missing"; line 5: unknown event "issue" for backend "nosettings"`,
+		},
+		{
+			desc:    "valid config",
+			content: "backends:\n- name: nosettings\n- name: settings\n  events:\n  - name: issues\n    actions:\n    - opened\n  settings:\n    urls:\n    - https://test.com",
+			err:     "",
+		},
+	}
+
+	for _, test := range tests {
+		cfg, err := Parse([]byte(test.content))
+		if err != nil {
+			t.Fatalf("description: %s, error parsing content: %s", test.desc, err.Error())
+		}
+
+		err = cfg.Validate(schemas, map[string]bool{"deploy_review": true})
+		if (err == nil && test.err != "") || (err != nil && err.Error() != test.err) {
+			t.Errorf("description: %s, error received: %v, expected: %s", test.desc, err, test.err)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	cfg, err := Parse([]byte("backends:\n- name: empty\n- name: settings\n  settings:\n    urls:\n    - https://test.com"))
+	if err != nil {
+		t.Fatalf("error parsing content: %s", err.Error())
+	}
+
+	tests := []struct {
+		desc     string
+		name     string
+		settings testSettings
+	}{
+		{
+			desc:     "no settings block",
+			name:     "empty",
+			settings: testSettings{},
+		},
+		{
+			desc:     "settings block decoded",
+			name:     "settings",
+			settings: testSettings{URLs: []string{"https://test.com"}},
+		},
+	}
+
+	for _, test := range tests {
+		bknd, ok := cfg.Backend(test.name)
+		if !ok {
+			t.Errorf("description: %s, backend %s not found", test.desc, test.name)
+			continue
+		}
+
+		s := testSettings{}
+		if err := bknd.Decode(&s); err != nil {
+			t.Errorf("description: %s, error decoding settings: %s", test.desc, err.Error())
+		}
+
+		if !reflect.DeepEqual(s, test.settings) {
+			t.Errorf("description: %s, settings received: %+v, expected: %+v", test.desc, s, test.settings)
+		}
+	}
+
+	if _, ok := cfg.Backend("missing"); ok {
+		t.Errorf("description: missing backend, received ok for unlisted backend")
+	}
+}
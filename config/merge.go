@@ -0,0 +1,107 @@
+package config
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// Merge strategies a backend block may use to combine with the base config block of the same name
+const (
+	MergeExtend   = "extend"
+	MergeOverride = "override"
+	MergeRemove   = "remove"
+)
+
+// Merges lists the valid values of the backend merge field
+var Merges = map[string]bool{
+	"":            true,
+	MergeExtend:   true,
+	MergeOverride: true,
+	MergeRemove:   true,
+}
+
+// Merge returns the base config with the backend blocks of the provided config applied on top
+func Merge(base, c *Config) *Config {
+	output := &Config{
+		DryRun: base.DryRun,
+	}
+	if c.DryRun != nil {
+		output.DryRun = c.DryRun
+	}
+
+	overrides := make(map[string]Backend)
+	for _, bknd := range c.Backends {
+		overrides[bknd.Name] = bknd
+	}
+
+	inherited := make(map[string]bool)
+	for _, baseBknd := range base.Backends {
+		inherited[baseBknd.Name] = true
+
+		bknd, ok := overrides[baseBknd.Name]
+		if !ok {
+			output.Backends = append(output.Backends, baseBknd)
+			continue
+		}
+
+		switch bknd.Merge {
+		case MergeRemove:
+			continue
+		case MergeOverride:
+		default:
+			if len(bknd.Events) == 0 {
+				bknd.Events = baseBknd.Events
+			}
+			bknd.Settings = mergeNodes(baseBknd.Settings, bknd.Settings)
+		}
+
+		bknd.Merge = ""
+		output.Backends = append(output.Backends, bknd)
+	}
+
+	for _, bknd := range c.Backends {
+		if inherited[bknd.Name] || bknd.Merge == MergeRemove {
+			continue
+		}
+
+		bknd.Merge = ""
+		output.Backends = append(output.Backends, bknd)
+	}
+
+	return output
+}
+
+// mergeNodes deep-merges the override node on top of the base node
+func mergeNodes(base, override yaml.Node) yaml.Node {
+	if override.Kind == 0 {
+		return base
+	}
+
+	if base.Kind != yaml.MappingNode || override.Kind != yaml.MappingNode {
+		return override
+	}
+
+	output := base
+	output.Content = append([]*yaml.Node{}, base.Content...)
+
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+
+		merged := false
+		for j := 0; j+1 < len(output.Content); j += 2 {
+			if output.Content[j].Value != key.Value {
+				continue
+			}
+
+			node := mergeNodes(*output.Content[j+1], *value)
+			output.Content[j+1] = &node
+			merged = true
+			break
+		}
+
+		if !merged {
+			output.Content = append(output.Content, key, value)
+		}
+	}
+
+	return output
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMerge(t *testing.T) {
+	base := `backends:
+- name: assignissue
+  events:
+  - name: issues
+    actions:
+    - opened
+  settings:
+    contributors:
+    - obi-wan
+    options:
+      threshold: 1
+      labels: true
+- name: projectboard
+  settings:
+    urls:
+    - https://coruscant.com
+- name: estimatepr
+`
+
+	tests := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc:    "no backends listed",
+			content: "backends: []",
+			expected: `backends:
+    - name: assignissue
+      events:
+        - name: issues
+          actions:
+            - opened
+      settings:
+        contributors:
+            - obi-wan
+        options:
+            threshold: 1
+            labels: true
+    - name: projectboard
+      settings:
+        urls:
+            - https://coruscant.com
+    - name: estimatepr
+`,
+		},
+		{
+			desc: "extending backend",
+			content: `backends:
+- name: assignissue
+  settings:
+    contributors:
+    - anakin
+    options:
+      threshold: 2
+`,
+			expected: `backends:
+    - name: assignissue
+      events:
+        - name: issues
+          actions:
+            - opened
+      settings:
+        contributors:
+            - anakin
+        options:
+            threshold: 2
+            labels: true
+    - name: projectboard
+      settings:
+        urls:
+            - https://coruscant.com
+    - name: estimatepr
+`,
+		},
+		{
+			desc: "overriding and removing backends",
+			content: `backends:
+- name: assignissue
+  merge: override
+  events:
+  - name: issues
+- name: projectboard
+  merge: remove
+`,
+			expected: `backends:
+    - name: assignissue
+      events:
+        - name: issues
+    - name: estimatepr
+`,
+		},
+		{
+			desc: "backends not in base config",
+			content: `backends:
+- name: estimatepr
+  events:
+  - name: pull_request
+- name: custom
+  merge: extend
+- name: removed
+  merge: remove
+`,
+			expected: `backends:
+    - name: assignissue
+      events:
+        - name: issues
+          actions:
+            - opened
+      settings:
+        contributors:
+            - obi-wan
+        options:
+            threshold: 1
+            labels: true
+    - name: projectboard
+      settings:
+        urls:
+            - https://coruscant.com
+    - name: estimatepr
+      events:
+        - name: pull_request
+    - name: custom
+`,
+		},
+	}
+
+	for _, test := range tests {
+		baseCfg, err := Parse([]byte(base))
+		if err != nil {
+			t.Fatalf("description: %s, error parsing base: %s", test.desc, err.Error())
+		}
+
+		cfg, err := Parse([]byte(test.content))
+		if err != nil {
+			t.Fatalf("description: %s, error parsing content: %s", test.desc, err.Error())
+		}
+
+		output, err := yaml.Marshal(Merge(baseCfg, cfg))
+		if err != nil {
+			t.Fatalf("description: %s, error marshalling config: %s", test.desc, err.Error())
+		}
+
+		if string(output) != test.expected {
+			t.Errorf("description: %s, config received:\n%s\nexpected:\n%s", test.desc, output, test.expected)
+		}
+	}
+}
+
+func TestMergeDryRun(t *testing.T) {
+	tests := []struct {
+		desc     string
+		base     string
+		content  string
+		expected *DryRun
+	}{
+		{
+			desc:     "no dry run blocks",
+			base:     "backends: []",
+			content:  "backends: []",
+			expected: nil,
+		},
+		{
+			desc:     "base dry run inherited",
+			base:     "dry_run:\n  enabled: true\n  comment: true",
+			content:  "backends: []",
+			expected: &DryRun{Enabled: true, Comment: true},
+		},
+		{
+			desc:     "repo dry run replaces base",
+			base:     "dry_run:\n  enabled: true\n  comment: true",
+			content:  "dry_run:\n  enabled: false",
+			expected: &DryRun{Enabled: false},
+		},
+	}
+
+	for _, test := range tests {
+		baseCfg, err := Parse([]byte(test.base))
+		if err != nil {
+			t.Fatalf("description: %s, error parsing base: %s", test.desc, err.Error())
+		}
+
+		cfg, err := Parse([]byte(test.content))
+		if err != nil {
+			t.Fatalf("description: %s, error parsing content: %s", test.desc, err.Error())
+		}
+
+		if output := Merge(baseCfg, cfg); !reflect.DeepEqual(output.DryRun, test.expected) {
+			t.Errorf("description: %s, dry run received: %+v, expected: %+v", test.desc, output.DryRun, test.expected)
+		}
+	}
+}
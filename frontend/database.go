@@ -1,28 +1,82 @@
 package frontend
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	bolt "go.etcd.io/bbolt"
 )
 
 type dynamoDBClient interface {
 	Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error)
 	UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error)
 }
 
-// Database provides an interface to DynamoDB data storage
+// Database provides an interface to installation, config, and delivery storage
 type Database interface {
 	Put(input installConfig) error
+	PutInstallation(input installation) error
+	PutRepo(input repository) error
 	Get(key interface{}) (installConfig, error)
+	PutConfig(input cachedConfig) error
+	GetConfig(path string) (cachedConfig, error)
+	PutDelivery(id string, expires time.Time) (bool, error)
+	DeleteDelivery(id string) error
 }
 
-// NewDatabase creates a new instance of a Database implementation
-func NewDatabase() Database {
+// installation records the app an installation belongs to
+type installation struct {
+	ID    int64 `json:"id"`
+	AppID int64 `json:"app_id"`
+}
+
+// repository records the installation a repo is covered by
+type repository struct {
+	FullName       string `json:"full_name"`
+	InstallationID int64  `json:"installation_id"`
+}
+
+// cachedConfig is a cached .heupr.yml file, or a record that the repo has none, keyed by its repo path
+type cachedConfig struct {
+	Path    string
+	Content string
+	Missing bool
+	Expires time.Time
+}
+
+// errNotInstalled is returned by Get when no installation matches the key
+var errNotInstalled = errors.New("installation not found")
+
+// errNotCached is returned by GetConfig when no config is cached for the path
+var errNotCached = errors.New("config not cached")
+
+// NewDatabase creates the Database described by the spec: "dynamodb" (the default), "memory", or "bolt:<file>"
+func NewDatabase(spec string) (Database, error) {
+	switch {
+	case spec == "" || spec == "dynamodb":
+		return NewDynamoDatabase(), nil
+	case spec == "memory":
+		return NewMemoryDatabase(), nil
+	case strings.HasPrefix(spec, "bolt:"):
+		return NewBoltDatabase(strings.TrimPrefix(spec, "bolt:"))
+	}
+
+	return nil, fmt.Errorf("unsupported database: %s", spec)
+}
+
+// NewDynamoDatabase creates a Database stored in the heupr DynamoDB tables
+func NewDynamoDatabase() Database {
 	return &db{
 		dynamodb: dynamodb.New(session.New()),
 	}
@@ -41,31 +95,16 @@ func (d *db) Put(input installConfig) error {
 				N: aws.String(strconv.FormatInt(input.AppID, 10)),
 			},
 		},
-		ReturnValues: aws.String("ALL_NEW"),
-	}
-
-	if input.FullName == "" {
-		log.Println("installation")
-		updateInput.ExpressionAttributeValues = map[string]*dynamodb.AttributeValue{
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
 			":webhook_secret": {
 				S: aws.String(input.WebhookSecret),
 			},
 			":pem": {
 				S: aws.String(input.PEM),
 			},
-		}
-		updateInput.UpdateExpression = aws.String("set webhook_secret = :webhook_secret, pem = :pem")
-	} else {
-		log.Println("event")
-		updateInput.ExpressionAttributeValues = map[string]*dynamodb.AttributeValue{
-			":full_name": {
-				S: aws.String(input.FullName),
-			},
-			":installation_id": {
-				N: aws.String(strconv.FormatInt(input.InstallationID, 10)),
-			},
-		}
-		updateInput.UpdateExpression = aws.String("set full_name = :full_name, installation_id = :installation_id")
+		},
+		UpdateExpression: aws.String("set webhook_secret = :webhook_secret, pem = :pem"),
+		ReturnValues:     aws.String("ALL_NEW"),
 	}
 
 	log.Printf("update input: %s\n", updateInput)
@@ -79,61 +118,134 @@ func (d *db) Put(input installConfig) error {
 	return nil
 }
 
-func (d *db) Get(key interface{}) (installConfig, error) {
-	keyType := fmt.Sprintf("%T", key) // NOTE: Better/more performent alternative should be implemented
-	log.Printf("get input: %v, type: %s\n", key, keyType)
+func (d *db) PutInstallation(input installation) error {
+	log.Printf("put installation input: %+v\n", input)
+	updateInput := dynamodb.UpdateItemInput{
+		TableName: aws.String("heupr-installations"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"installation_id": {
+				N: aws.String(strconv.FormatInt(input.ID, 10)),
+			},
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":app_id": {
+				N: aws.String(strconv.FormatInt(input.AppID, 10)),
+			},
+		},
+		UpdateExpression: aws.String("set app_id = :app_id"),
+	}
 
-	queryInput := &dynamodb.QueryInput{
-		TableName: aws.String("heupr"),
+	if _, err := d.dynamodb.UpdateItem(&updateInput); err != nil {
+		return fmt.Errorf("put installation item error: %s", err.Error())
 	}
 
-	if keyType == "int64" {
-		log.Println("apps")
-		queryInput.KeyConditionExpression = aws.String("app_id = :app_id")
-		queryInput.ExpressionAttributeValues = map[string]*dynamodb.AttributeValue{
-			":app_id": {
-				N: aws.String(strconv.FormatInt(key.(int64), 10)),
+	return nil
+}
+
+func (d *db) PutRepo(input repository) error {
+	log.Printf("put repo input: %+v\n", input)
+	updateInput := dynamodb.UpdateItemInput{
+		TableName: aws.String("heupr-repos"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"full_name": {
+				S: aws.String(input.FullName),
 			},
-		}
-	} else if keyType == "string" {
-		log.Println("repos")
-		queryInput.KeyConditionExpression = aws.String("full_name = :full_name")
-		queryInput.ExpressionAttributeValues = map[string]*dynamodb.AttributeValue{
-			":full_name": {
-				S: aws.String(key.(string)),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":installation_id": {
+				N: aws.String(strconv.FormatInt(input.InstallationID, 10)),
 			},
-		}
-		queryInput.IndexName = aws.String("repos")
+		},
+		UpdateExpression: aws.String("set installation_id = :installation_id"),
+	}
+
+	if _, err := d.dynamodb.UpdateItem(&updateInput); err != nil {
+		return fmt.Errorf("put repo item error: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Get returns the app record for an int64 app ID or the installation of a string repo name
+func (d *db) Get(key interface{}) (installConfig, error) {
+	log.Printf("get input: %v, type: %T\n", key, key)
+
+	switch k := key.(type) {
+	case int64:
+		return d.getApp(k)
+	case string:
+		return d.getRepo(k)
+	}
+
+	return installConfig{}, fmt.Errorf("unsupported key type: %T", key)
+}
+
+// queryItem returns the first item in the table, or its index if named, matching the key or nil if none exists
+func (d *db) queryItem(table, index, name string, value *dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(table),
+		KeyConditionExpression: aws.String(name + " = :key"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":key": value,
+		},
+	}
+	if index != "" {
+		queryInput.IndexName = aws.String(index)
 	}
 
 	log.Printf("query input: %+v\n", queryInput)
 
-	output := installConfig{}
 	result, err := d.dynamodb.Query(queryInput)
 	if err != nil {
-		return output, fmt.Errorf("get item error: %s", err.Error())
+		return nil, fmt.Errorf("get item error: %s", err.Error())
+	}
+
+	if len(result.Items) == 0 {
+		return nil, nil
+	}
+	return result.Items[0], nil
+}
+
+func itemInt(item map[string]*dynamodb.AttributeValue, name string) (int64, error) {
+	value, ok := item[name]
+	if !ok || value.N == nil {
+		return 0, fmt.Errorf("key not provided: %s", name)
+	}
+
+	output, err := strconv.ParseInt(*value.N, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("convert item int: %s", err.Error())
+	}
+	return output, nil
+}
+
+func (d *db) getApp(appID int64) (installConfig, error) {
+	output := installConfig{}
+	item, err := d.queryItem("heupr", "", "app_id", &dynamodb.AttributeValue{
+		N: aws.String(strconv.FormatInt(appID, 10)),
+	})
+	if err != nil {
+		return output, err
+	}
+
+	if item == nil {
+		return output, errNotInstalled
 	}
 
-	for key, item := range result.Items[0] {
+	for key, value := range item {
 		switch key {
 		case "app_id":
-			value, err := strconv.ParseInt(*item.N, 10, 64)
+			appID, err := itemInt(item, key)
 			if err != nil {
-				return output, fmt.Errorf("convert item int: %s", err.Error())
+				return output, err
 			}
-			output.AppID = value
+			output.AppID = appID
 		case "pem":
-			output.PEM = *item.S
+			output.PEM = *value.S
 		case "webhook_secret":
-			output.WebhookSecret = *item.S
-		case "installation_id":
-			value, err := strconv.ParseInt(*item.N, 10, 64)
-			if err != nil {
-				return output, fmt.Errorf("convert item int: %s", err.Error())
-			}
-			output.InstallationID = value
-		case "full_name":
-			output.FullName = *item.S
+			output.WebhookSecret = *value.S
+		case "full_name", "installation_id":
+			// NOTE: Set on app items before repos were stored separately
 		default:
 			return output, fmt.Errorf("key not provided: %s", key)
 		}
@@ -142,3 +254,524 @@ func (d *db) Get(key interface{}) (installConfig, error) {
 	log.Println("successful get method invocation")
 	return output, nil
 }
+
+func (d *db) getRepo(fullName string) (installConfig, error) {
+	item, err := d.queryItem("heupr-repos", "", "full_name", &dynamodb.AttributeValue{
+		S: aws.String(fullName),
+	})
+	if err != nil {
+		return installConfig{}, err
+	}
+
+	if item == nil {
+		return d.getLegacyRepo(fullName)
+	}
+
+	installationID, err := itemInt(item, "installation_id")
+	if err != nil {
+		return installConfig{}, err
+	}
+
+	item, err = d.queryItem("heupr-installations", "", "installation_id", &dynamodb.AttributeValue{
+		N: aws.String(strconv.FormatInt(installationID, 10)),
+	})
+	if err != nil {
+		return installConfig{}, err
+	}
+
+	if item == nil {
+		return installConfig{}, errNotInstalled
+	}
+
+	appID, err := itemInt(item, "app_id")
+	if err != nil {
+		return installConfig{}, err
+	}
+
+	output, err := d.getApp(appID)
+	if err != nil {
+		return installConfig{}, err
+	}
+
+	output.FullName = fullName
+	output.InstallationID = installationID
+	return output, nil
+}
+
+// getLegacyRepo looks up a repo recorded on its app item and copies it into the repo tables
+func (d *db) getLegacyRepo(fullName string) (installConfig, error) {
+	item, err := d.queryItem("heupr", "repos", "full_name", &dynamodb.AttributeValue{
+		S: aws.String(fullName),
+	})
+	if err != nil {
+		return installConfig{}, err
+	}
+
+	if item == nil {
+		return installConfig{}, errNotInstalled
+	}
+
+	appID, err := itemInt(item, "app_id")
+	if err != nil {
+		return installConfig{}, err
+	}
+
+	installationID, err := itemInt(item, "installation_id")
+	if err != nil {
+		return installConfig{}, err
+	}
+
+	output, err := d.getApp(appID)
+	if err != nil {
+		return installConfig{}, err
+	}
+
+	log.Printf("migrating legacy repo: %s, installation id: %d\n", fullName, installationID)
+	if err := d.PutInstallation(installation{ID: installationID, AppID: appID}); err != nil {
+		log.Printf("error migrating legacy installation: %s\n", err.Error())
+	} else if err := d.PutRepo(repository{FullName: fullName, InstallationID: installationID}); err != nil {
+		log.Printf("error migrating legacy repo: %s\n", err.Error())
+	}
+
+	output.FullName = fullName
+	output.InstallationID = installationID
+	return output, nil
+}
+
+func (d *db) PutConfig(input cachedConfig) error {
+	log.Printf("put config input: %+v\n", input)
+	updateInput := dynamodb.UpdateItemInput{
+		TableName: aws.String("heupr-config"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"config_path": {
+				S: aws.String(input.Path),
+			},
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":content": {
+				S: aws.String(input.Content),
+			},
+			":missing": {
+				BOOL: aws.Bool(input.Missing),
+			},
+			":expires": {
+				N: aws.String(strconv.FormatInt(input.Expires.Unix(), 10)),
+			},
+		},
+		UpdateExpression: aws.String("set file_content = :content, file_missing = :missing, expires_at = :expires"),
+	}
+
+	if _, err := d.dynamodb.UpdateItem(&updateInput); err != nil {
+		return fmt.Errorf("put config item error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (d *db) GetConfig(path string) (cachedConfig, error) {
+	log.Printf("get config input: %s\n", path)
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String("heupr-config"),
+		KeyConditionExpression: aws.String("config_path = :path"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":path": {
+				S: aws.String(path),
+			},
+		},
+	}
+
+	output := cachedConfig{
+		Path: path,
+	}
+	result, err := d.dynamodb.Query(queryInput)
+	if err != nil {
+		return output, fmt.Errorf("get config item error: %s", err.Error())
+	}
+
+	if len(result.Items) == 0 {
+		return output, errNotCached
+	}
+
+	item := result.Items[0]
+	if value, ok := item["file_content"]; ok && value.S != nil {
+		output.Content = *value.S
+	}
+	if value, ok := item["file_missing"]; ok && value.BOOL != nil {
+		output.Missing = *value.BOOL
+	}
+	if value, ok := item["expires_at"]; ok && value.N != nil {
+		expires, err := strconv.ParseInt(*value.N, 10, 64)
+		if err != nil {
+			return output, fmt.Errorf("get config item error: %s", err.Error())
+		}
+		output.Expires = time.Unix(expires, 0)
+	}
+
+	return output, nil
+}
+
+// PutDelivery records a webhook delivery ID until it expires, returning false if already received
+func (d *db) PutDelivery(id string, expires time.Time) (bool, error) {
+	log.Printf("put delivery input: %s, expires: %s\n", id, expires)
+	updateInput := dynamodb.UpdateItemInput{
+		TableName: aws.String("heupr-deliveries"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"delivery_id": {
+				S: aws.String(id),
+			},
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":expires": {
+				N: aws.String(strconv.FormatInt(expires.Unix(), 10)),
+			},
+			":now": {
+				N: aws.String(strconv.FormatInt(time.Now().Unix(), 10)),
+			},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(delivery_id) OR expires_at < :now"),
+		UpdateExpression:    aws.String("set expires_at = :expires"),
+	}
+
+	if _, err := d.dynamodb.UpdateItem(&updateInput); err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return false, nil
+		}
+		return false, fmt.Errorf("put delivery item error: %s", err.Error())
+	}
+
+	return true, nil
+}
+
+// DeleteDelivery removes a webhook delivery record so the delivery may be processed again
+func (d *db) DeleteDelivery(id string) error {
+	log.Printf("delete delivery input: %s\n", id)
+	deleteInput := dynamodb.DeleteItemInput{
+		TableName: aws.String("heupr-deliveries"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"delivery_id": {
+				S: aws.String(id),
+			},
+		},
+	}
+
+	if _, err := d.dynamodb.DeleteItem(&deleteInput); err != nil {
+		return fmt.Errorf("delete delivery item error: %s", err.Error())
+	}
+
+	return nil
+}
+
+// NewMemoryDatabase creates a Database held in process memory for local development and tests
+func NewMemoryDatabase() Database {
+	return &memoryDatabase{
+		apps:          make(map[int64]installConfig),
+		installations: make(map[int64]installation),
+		repos:         make(map[string]repository),
+		configs:       make(map[string]cachedConfig),
+		deliveries:    make(map[string]time.Time),
+	}
+}
+
+type memoryDatabase struct {
+	mu            sync.Mutex
+	apps          map[int64]installConfig
+	installations map[int64]installation
+	repos         map[string]repository
+	configs       map[string]cachedConfig
+	deliveries    map[string]time.Time
+}
+
+func (d *memoryDatabase) Put(input installConfig) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.apps[input.AppID] = appRecord(input)
+	return nil
+}
+
+func (d *memoryDatabase) PutInstallation(input installation) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.installations[input.ID] = input
+	return nil
+}
+
+func (d *memoryDatabase) PutRepo(input repository) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.repos[input.FullName] = input
+	return nil
+}
+
+func (d *memoryDatabase) Get(key interface{}) (installConfig, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch k := key.(type) {
+	case int64:
+		if output, ok := d.apps[k]; ok {
+			return output, nil
+		}
+	case string:
+		repo, ok := d.repos[k]
+		if !ok {
+			break
+		}
+		inst, ok := d.installations[repo.InstallationID]
+		if !ok {
+			break
+		}
+		if output, ok := d.apps[inst.AppID]; ok {
+			output.FullName = repo.FullName
+			output.InstallationID = inst.ID
+			return output, nil
+		}
+	}
+
+	return installConfig{}, errNotInstalled
+}
+
+func (d *memoryDatabase) PutConfig(input cachedConfig) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.configs[input.Path] = input
+	return nil
+}
+
+func (d *memoryDatabase) GetConfig(path string) (cachedConfig, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	output, ok := d.configs[path]
+	if !ok {
+		return cachedConfig{Path: path}, errNotCached
+	}
+	return output, nil
+}
+
+func (d *memoryDatabase) PutDelivery(id string, expires time.Time) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if existing, ok := d.deliveries[id]; ok && existing.After(time.Now()) {
+		return false, nil
+	}
+	d.deliveries[id] = expires
+	return true, nil
+}
+
+func (d *memoryDatabase) DeleteDelivery(id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.deliveries, id)
+	return nil
+}
+
+// appRecord returns the app credentials from the input, which are all that app records store
+func appRecord(input installConfig) installConfig {
+	return installConfig{
+		AppID:         input.AppID,
+		PEM:           input.PEM,
+		WebhookSecret: input.WebhookSecret,
+	}
+}
+
+var (
+	appsBucket          = []byte("apps")
+	installationsBucket = []byte("installations")
+	reposBucket         = []byte("repos")
+	configsBucket       = []byte("configs")
+	deliveriesBucket    = []byte("deliveries")
+)
+
+// NewBoltDatabase creates a Database stored in the BoltDB file at the path
+func NewBoltDatabase(path string) (Database, error) {
+	store, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, errors.New("error opening bolt database: " + err.Error())
+	}
+
+	err = store.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{appsBucket, installationsBucket, reposBucket, configsBucket, deliveriesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		store.Close()
+		return nil, errors.New("error creating bolt buckets: " + err.Error())
+	}
+
+	return &boltDatabase{
+		store: store,
+	}, nil
+}
+
+type boltDatabase struct {
+	store *bolt.DB
+}
+
+func (d *boltDatabase) Put(input installConfig) error {
+	if err := d.put(appsBucket, strconv.FormatInt(input.AppID, 10), appRecord(input)); err != nil {
+		return fmt.Errorf("put item error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (d *boltDatabase) PutInstallation(input installation) error {
+	if err := d.put(installationsBucket, strconv.FormatInt(input.ID, 10), input); err != nil {
+		return fmt.Errorf("put installation item error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (d *boltDatabase) PutRepo(input repository) error {
+	if err := d.put(reposBucket, input.FullName, input); err != nil {
+		return fmt.Errorf("put repo item error: %s", err.Error())
+	}
+
+	return nil
+}
+
+// put stores the value as JSON under the key in the bucket
+func (d *boltDatabase) put(bucket []byte, key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return d.store.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), value)
+	})
+}
+
+// get decodes the JSON value under the key in the bucket, reporting whether it exists
+func get(tx *bolt.Tx, bucket []byte, key string, v interface{}) (bool, error) {
+	value := tx.Bucket(bucket).Get([]byte(key))
+	if value == nil {
+		return false, nil
+	}
+
+	return true, json.Unmarshal(value, v)
+}
+
+func (d *boltDatabase) Get(key interface{}) (installConfig, error) {
+	output := installConfig{}
+	found := false
+
+	err := d.store.View(func(tx *bolt.Tx) error {
+		var err error
+		switch k := key.(type) {
+		case int64:
+			found, err = get(tx, appsBucket, strconv.FormatInt(k, 10), &output)
+		case string:
+			repo := repository{}
+			if found, err = get(tx, reposBucket, k, &repo); !found || err != nil {
+				return err
+			}
+
+			inst := installation{}
+			if found, err = get(tx, installationsBucket, strconv.FormatInt(repo.InstallationID, 10), &inst); !found || err != nil {
+				return err
+			}
+
+			if found, err = get(tx, appsBucket, strconv.FormatInt(inst.AppID, 10), &output); !found || err != nil {
+				return err
+			}
+
+			output.FullName = repo.FullName
+			output.InstallationID = inst.ID
+		}
+		return err
+	})
+	if err != nil {
+		return installConfig{}, fmt.Errorf("get item error: %s", err.Error())
+	}
+
+	if !found {
+		return installConfig{}, errNotInstalled
+	}
+	return output, nil
+}
+
+func (d *boltDatabase) PutConfig(input cachedConfig) error {
+	value, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("put config item error: %s", err.Error())
+	}
+
+	err = d.store.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(configsBucket).Put([]byte(input.Path), value)
+	})
+	if err != nil {
+		return fmt.Errorf("put config item error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (d *boltDatabase) GetConfig(path string) (cachedConfig, error) {
+	output := cachedConfig{
+		Path: path,
+	}
+	found := false
+
+	err := d.store.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(configsBucket).Get([]byte(path))
+		if value == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(value, &output)
+	})
+	if err != nil {
+		return output, fmt.Errorf("get config item error: %s", err.Error())
+	}
+
+	if !found {
+		return output, errNotCached
+	}
+	return output, nil
+}
+
+// PutDelivery records a webhook delivery ID until it expires, overwriting expired records
+func (d *boltDatabase) PutDelivery(id string, expires time.Time) (bool, error) {
+	created := false
+
+	err := d.store.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(deliveriesBucket)
+		if value := bucket.Get([]byte(id)); value != nil {
+			existing, err := strconv.ParseInt(string(value), 10, 64)
+			if err == nil && existing >= time.Now().Unix() {
+				return nil
+			}
+		}
+
+		created = true
+		return bucket.Put([]byte(id), []byte(strconv.FormatInt(expires.Unix(), 10)))
+	})
+	if err != nil {
+		return false, fmt.Errorf("put delivery item error: %s", err.Error())
+	}
+
+	return created, nil
+}
+
+func (d *boltDatabase) DeleteDelivery(id string) error {
+	err := d.store.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(deliveriesBucket).Delete([]byte(id))
+	})
+	if err != nil {
+		return fmt.Errorf("delete delivery item error: %s", err.Error())
+	}
+
+	return nil
+}
@@ -2,39 +2,248 @@ package frontend
 
 import (
 	"errors"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 )
 
 func TestNewDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "heupr-database")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		desc string
+		spec string
+		err  string
+	}{
+		{
+			desc: "default database",
+			spec: "",
+			err:  "",
+		},
+		{
+			desc: "unsupported database",
+			spec: "sqlite:archives.db",
+			err:  "unsupported database: sqlite:archives.db",
+		},
+		{
+			desc: "dynamodb database",
+			spec: "dynamodb",
+			err:  "",
+		},
+		{
+			desc: "memory database",
+			spec: "memory",
+			err:  "",
+		},
+		{
+			desc: "bolt database",
+			spec: "bolt:" + filepath.Join(dir, "heupr.db"),
+			err:  "",
+		},
+		{
+			desc: "error opening bolt database",
+			spec: "bolt:" + filepath.Join(dir, "missing", "heupr.db"),
+			err:  "error opening bolt database: open " + filepath.Join(dir, "missing", "heupr.db") + ": no such file or directory",
+		},
+	}
+
 	os.Setenv("AWS_REGION", "us-east-1")
+	defer os.Unsetenv("AWS_REGION")
+
+	for _, test := range tests {
+		db, err := NewDatabase(test.spec)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
+			}
+			continue
+		}
+
+		if test.err != "" {
+			t.Errorf("description: %s, no error received, expected: %s", test.desc, test.err)
+		}
+
+		if db == nil {
+			t.Errorf("description: %s, no database returned", test.desc)
+		}
+	}
+}
+
+func testDatabase(t *testing.T, desc string, d Database) {
+	if _, err := d.Get(int64(66)); err != errNotInstalled {
+		t.Errorf("description: %s, missing app error received: %v, expected: %v", desc, err, errNotInstalled)
+	}
 
-	db := NewDatabase()
-	if db == nil {
-		t.Errorf("description: create database error, received: %+v", db)
+	if err := d.Put(installConfig{AppID: 66, WebhookSecret: "secret", PEM: "execute order 66"}); err != nil {
+		t.Fatalf("description: %s, error putting app: %s", desc, err.Error())
 	}
 
-	os.Unsetenv("AWS_REGION")
+	installs := []struct {
+		id    int64
+		repos []string
+	}{
+		{4, []string{"jedi/temple", "jedi/archives"}},
+		{5, []string{"sith/temple"}},
+	}
+	for _, install := range installs {
+		if err := d.PutInstallation(installation{ID: install.id, AppID: 66}); err != nil {
+			t.Fatalf("description: %s, error putting installation: %s", desc, err.Error())
+		}
+		for _, fullName := range install.repos {
+			if err := d.PutRepo(repository{FullName: fullName, InstallationID: install.id}); err != nil {
+				t.Fatalf("description: %s, error putting repo: %s", desc, err.Error())
+			}
+		}
+	}
+
+	lookups := []struct {
+		key      interface{}
+		expected installConfig
+	}{
+		{int64(66), installConfig{AppID: 66, PEM: "execute order 66", WebhookSecret: "secret"}},
+		{"jedi/temple", installConfig{AppID: 66, FullName: "jedi/temple", PEM: "execute order 66", WebhookSecret: "secret", InstallationID: 4}},
+		{"jedi/archives", installConfig{AppID: 66, FullName: "jedi/archives", PEM: "execute order 66", WebhookSecret: "secret", InstallationID: 4}},
+		{"sith/temple", installConfig{AppID: 66, FullName: "sith/temple", PEM: "execute order 66", WebhookSecret: "secret", InstallationID: 5}},
+	}
+	for _, lookup := range lookups {
+		received, err := d.Get(lookup.key)
+		if err != nil {
+			t.Errorf("description: %s, error getting %v: %s", desc, lookup.key, err.Error())
+		}
+		if received != lookup.expected {
+			t.Errorf("description: %s, key: %v, received: %+v, expected: %+v", desc, lookup.key, received, lookup.expected)
+		}
+	}
+
+	if _, err := d.Get("rebels/base"); err != errNotInstalled {
+		t.Errorf("description: %s, missing repo error received: %v, expected: %v", desc, err, errNotInstalled)
+	}
+
+	if err := d.PutRepo(repository{FullName: "rebels/base", InstallationID: 6}); err != nil {
+		t.Fatalf("description: %s, error putting repo: %s", desc, err.Error())
+	}
+	if _, err := d.Get("rebels/base"); err != errNotInstalled {
+		t.Errorf("description: %s, missing installation error received: %v, expected: %v", desc, err, errNotInstalled)
+	}
+
+	if _, err := d.GetConfig("jedi/temple/.heupr.yml"); err != errNotCached {
+		t.Errorf("description: %s, missing config error received: %v, expected: %v", desc, err, errNotCached)
+	}
+
+	cached := cachedConfig{Path: "jedi/temple/.heupr.yml", Content: "backends: []", Expires: time.Unix(1138, 0)}
+	if err := d.PutConfig(cached); err != nil {
+		t.Errorf("description: %s, error putting config: %s", desc, err.Error())
+	}
+	received, err := d.GetConfig(cached.Path)
+	if err != nil || !received.Expires.Equal(cached.Expires) {
+		t.Errorf("description: %s, config expires received: %s, error: %v, expected: %s", desc, received.Expires, err, cached.Expires)
+	}
+	received.Expires = cached.Expires
+	if received != cached {
+		t.Errorf("description: %s, config received: %+v, expected: %+v", desc, received, cached)
+	}
+
+	deliveries := []struct {
+		id      string
+		expires time.Time
+		created bool
+	}{
+		{"order-66", time.Now().Add(time.Hour), true},
+		{"order-66", time.Now().Add(time.Hour), false},
+		{"order-65", time.Now().Add(-time.Hour), true},
+		{"order-65", time.Now().Add(time.Hour), true},
+	}
+	for _, delivery := range deliveries {
+		created, err := d.PutDelivery(delivery.id, delivery.expires)
+		if err != nil || created != delivery.created {
+			t.Errorf("description: %s, delivery %s created: %t, error: %v, expected: %t", desc, delivery.id, created, err, delivery.created)
+		}
+	}
+
+	if err := d.DeleteDelivery("order-66"); err != nil {
+		t.Errorf("description: %s, error deleting delivery: %s", desc, err.Error())
+	}
+	if created, err := d.PutDelivery("order-66", time.Now().Add(time.Hour)); err != nil || !created {
+		t.Errorf("description: %s, deleted delivery not recorded again, error: %v", desc, err)
+	}
+}
+
+func TestMemoryDatabase(t *testing.T) {
+	testDatabase(t, "memory database", NewMemoryDatabase())
+}
+
+func TestBoltDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "heupr-database")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "heupr.db")
+	d, err := NewBoltDatabase(path)
+	if err != nil {
+		t.Fatalf("description: error creating bolt database: %s", err.Error())
+	}
+
+	testDatabase(t, "bolt database", d)
+	d.(*boltDatabase).store.Close()
+
+	reopened, err := NewBoltDatabase(path)
+	if err != nil {
+		t.Fatalf("description: error reopening bolt database: %s", err.Error())
+	}
+	defer reopened.(*boltDatabase).store.Close()
+
+	if received, err := reopened.Get("sith/temple"); err != nil || received.InstallationID != 5 {
+		t.Errorf("description: installation not persisted, received: %+v, error: %v", received, err)
+	}
 }
 
 type mockDBClient struct {
 	queryItemOutput  *dynamodb.QueryOutput
+	queryOutputs     map[string]*dynamodb.QueryOutput
 	queryErr         error
 	updateItemOutput *dynamodb.UpdateItemOutput
 	updateItemErr    error
+	deleteItemErr    error
+	updatedTables    []string
 }
 
 func (m *mockDBClient) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	if m.queryOutputs != nil {
+		key := aws.StringValue(input.TableName)
+		if input.IndexName != nil {
+			key += "/" + aws.StringValue(input.IndexName)
+		}
+		output, ok := m.queryOutputs[key]
+		if !ok {
+			output = &dynamodb.QueryOutput{}
+		}
+		return output, m.queryErr
+	}
 	return m.queryItemOutput, m.queryErr
 }
 
 func (m *mockDBClient) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	m.updatedTables = append(m.updatedTables, aws.StringValue(input.TableName))
 	return m.updateItemOutput, m.updateItemErr
 }
 
+func (m *mockDBClient) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	return &dynamodb.DeleteItemOutput{}, m.deleteItemErr
+}
+
 func TestPut(t *testing.T) {
 	tests := []struct {
 		desc             string
@@ -65,17 +274,6 @@ func TestPut(t *testing.T) {
 			updateItemErr:    nil,
 			err:              "",
 		},
-		{
-			desc: "successful invocation repo installation info",
-			config: installConfig{
-				WebhookSecret:  "secret",
-				InstallationID: 4,
-				FullName:       "Contingency Orders for the Grand Army of the Republic: Order Initiation, Orders 1 Through 150",
-			},
-			updateItemOutput: nil,
-			updateItemErr:    nil,
-			err:              "",
-		},
 	}
 
 	for _, test := range tests {
@@ -94,34 +292,166 @@ func TestPut(t *testing.T) {
 	}
 }
 
+func TestPutInstallation(t *testing.T) {
+	tests := []struct {
+		desc          string
+		updateItemErr error
+		err           string
+	}{
+		{
+			desc:          "error updating item",
+			updateItemErr: errors.New("mock update error"),
+			err:           "put installation item error: mock update error",
+		},
+		{
+			desc:          "successful invocation",
+			updateItemErr: nil,
+			err:           "",
+		},
+	}
+
+	for _, test := range tests {
+		client := &mockDBClient{
+			updateItemErr: test.updateItemErr,
+		}
+		db := db{
+			dynamodb: client,
+		}
+
+		err := db.PutInstallation(installation{ID: 2, AppID: 1})
+		if (err != nil || test.err != "") && (err == nil || err.Error() != test.err) {
+			t.Errorf("description: %s, error received: %v, expected: %s", test.desc, err, test.err)
+		}
+
+		if len(client.updatedTables) != 1 || client.updatedTables[0] != "heupr-installations" {
+			t.Errorf("description: %s, tables received: %v, expected: [heupr-installations]", test.desc, client.updatedTables)
+		}
+	}
+}
+
+func TestPutRepo(t *testing.T) {
+	tests := []struct {
+		desc          string
+		updateItemErr error
+		err           string
+	}{
+		{
+			desc:          "error updating item",
+			updateItemErr: errors.New("mock update error"),
+			err:           "put repo item error: mock update error",
+		},
+		{
+			desc:          "successful invocation",
+			updateItemErr: nil,
+			err:           "",
+		},
+	}
+
+	for _, test := range tests {
+		client := &mockDBClient{
+			updateItemErr: test.updateItemErr,
+		}
+		db := db{
+			dynamodb: client,
+		}
+
+		err := db.PutRepo(repository{FullName: "tatooine/mos-espa", InstallationID: 2})
+		if (err != nil || test.err != "") && (err == nil || err.Error() != test.err) {
+			t.Errorf("description: %s, error received: %v, expected: %s", test.desc, err, test.err)
+		}
+
+		if len(client.updatedTables) != 1 || client.updatedTables[0] != "heupr-repos" {
+			t.Errorf("description: %s, tables received: %v, expected: [heupr-repos]", test.desc, client.updatedTables)
+		}
+	}
+}
+
+func queryOutput(items ...map[string]*dynamodb.AttributeValue) *dynamodb.QueryOutput {
+	return &dynamodb.QueryOutput{
+		Items: items,
+	}
+}
+
 func TestGet(t *testing.T) {
+	app := map[string]*dynamodb.AttributeValue{
+		"app_id": {
+			N: aws.String("1"),
+		},
+		"pem": {
+			S: aws.String("tatoo-i-tatoo-ii-ghomrassen-guermessa-chenini"),
+		},
+		"webhook_secret": {
+			S: aws.String("skywalker"),
+		},
+	}
+
 	tests := []struct {
-		desc            string
-		key             interface{}
-		queryItemOutput *dynamodb.QueryOutput
-		queryErr        error
-		output          installConfig
-		err             string
+		desc         string
+		key          interface{}
+		queryOutputs map[string]*dynamodb.QueryOutput
+		queryErr     error
+		output       installConfig
+		updated      []string
+		err          string
 	}{
 		{
-			desc:            "error getting item",
-			key:             "watto",
-			queryItemOutput: nil,
-			queryErr:        errors.New("query mock error"),
-			output:          installConfig{},
-			err:             "get item error: query mock error",
+			desc:         "error getting item",
+			key:          "watto",
+			queryOutputs: map[string]*dynamodb.QueryOutput{},
+			queryErr:     errors.New("query mock error"),
+			output:       installConfig{},
+			err:          "get item error: query mock error",
+		},
+		{
+			desc:         "unsupported key type",
+			key:          66,
+			queryOutputs: map[string]*dynamodb.QueryOutput{},
+			queryErr:     nil,
+			output:       installConfig{},
+			err:          "unsupported key type: int",
+		},
+		{
+			desc:         "repo not found",
+			key:          "jar-jar",
+			queryOutputs: map[string]*dynamodb.QueryOutput{},
+			queryErr:     nil,
+			output:       installConfig{},
+			err:          "installation not found",
+		},
+		{
+			desc: "installation not found",
+			key:  "tatooine/mos-espa",
+			queryOutputs: map[string]*dynamodb.QueryOutput{
+				"heupr-repos": queryOutput(map[string]*dynamodb.AttributeValue{
+					"full_name":       {S: aws.String("tatooine/mos-espa")},
+					"installation_id": {N: aws.String("2")},
+				}),
+				"heupr": queryOutput(app),
+			},
+			queryErr: nil,
+			output:   installConfig{},
+			err:      "installation not found",
+		},
+		{
+			desc: "invalid repo installation id",
+			key:  "tatooine/mos-espa",
+			queryOutputs: map[string]*dynamodb.QueryOutput{
+				"heupr-repos": queryOutput(map[string]*dynamodb.AttributeValue{
+					"full_name":       {S: aws.String("tatooine/mos-espa")},
+					"installation_id": {N: aws.String("order-66")},
+				}),
+			},
+			queryErr: nil,
+			output:   installConfig{},
+			err:      `convert item int: strconv.ParseInt: parsing "order-66": invalid syntax`,
 		},
 		{
 			desc: "invalid key",
-			key:  "shmi",
-			queryItemOutput: &dynamodb.QueryOutput{
-				Items: []map[string]*dynamodb.AttributeValue{
-					map[string]*dynamodb.AttributeValue{
-						"mother": {
-							S: aws.String("skywalker"),
-						},
-					},
-				},
+			key:  int64(1),
+			queryOutputs: map[string]*dynamodb.QueryOutput{
+				"heupr": queryOutput(map[string]*dynamodb.AttributeValue{
+					"mother": {S: aws.String("skywalker")},
+				}),
 			},
 			queryErr: nil,
 			output:   installConfig{},
@@ -129,67 +459,200 @@ func TestGet(t *testing.T) {
 		},
 		{
 			desc: "successful string invocation",
-			key:  "anakin",
-			queryItemOutput: &dynamodb.QueryOutput{
-				Items: []map[string]*dynamodb.AttributeValue{
-					map[string]*dynamodb.AttributeValue{
-						"app_id": {
-							N: aws.String("1"),
-						},
-						"pem": {
-							S: aws.String("tatoo-i-tatoo-ii-ghomrassen-guermessa-chenini"),
-						},
-						"webhook_secret": {
-							S: aws.String("skywalker"),
-						},
-						"installation_id": {
-							N: aws.String("2"),
-						},
-						"full_name": {
-							S: aws.String("tatooine"),
-						},
-					},
-				},
+			key:  "tatooine/mos-espa",
+			queryOutputs: map[string]*dynamodb.QueryOutput{
+				"heupr-repos": queryOutput(map[string]*dynamodb.AttributeValue{
+					"full_name":       {S: aws.String("tatooine/mos-espa")},
+					"installation_id": {N: aws.String("2")},
+				}),
+				"heupr-installations": queryOutput(map[string]*dynamodb.AttributeValue{
+					"installation_id": {N: aws.String("2")},
+					"app_id":          {N: aws.String("1")},
+				}),
+				"heupr": queryOutput(app),
 			},
 			queryErr: nil,
 			output: installConfig{
 				AppID:          1,
+				FullName:       "tatooine/mos-espa",
 				PEM:            "tatoo-i-tatoo-ii-ghomrassen-guermessa-chenini",
 				WebhookSecret:  "skywalker",
 				InstallationID: 2,
 			},
 			err: "",
 		},
+		{
+			desc: "legacy repo migrated",
+			key:  "tatooine/mos-espa",
+			queryOutputs: map[string]*dynamodb.QueryOutput{
+				"heupr/repos": queryOutput(map[string]*dynamodb.AttributeValue{
+					"app_id":          {N: aws.String("1")},
+					"full_name":       {S: aws.String("tatooine/mos-espa")},
+					"installation_id": {N: aws.String("2")},
+				}),
+				"heupr": queryOutput(app),
+			},
+			queryErr: nil,
+			output: installConfig{
+				AppID:          1,
+				FullName:       "tatooine/mos-espa",
+				PEM:            "tatoo-i-tatoo-ii-ghomrassen-guermessa-chenini",
+				WebhookSecret:  "skywalker",
+				InstallationID: 2,
+			},
+			updated: []string{"heupr-installations", "heupr-repos"},
+			err:     "",
+		},
 		{
 			desc: "successful int64 invocation",
-			key:  int64(1038),
+			key:  int64(1),
+			queryOutputs: map[string]*dynamodb.QueryOutput{
+				"heupr": queryOutput(app),
+			},
+			queryErr: nil,
+			output: installConfig{
+				AppID:         1,
+				PEM:           "tatoo-i-tatoo-ii-ghomrassen-guermessa-chenini",
+				WebhookSecret: "skywalker",
+			},
+			err: "",
+		},
+		{
+			desc: "successful int64 invocation with legacy repo fields",
+			key:  int64(1),
+			queryOutputs: map[string]*dynamodb.QueryOutput{
+				"heupr": queryOutput(map[string]*dynamodb.AttributeValue{
+					"app_id":          {N: aws.String("1")},
+					"webhook_secret":  {S: aws.String("skywalker")},
+					"full_name":       {S: aws.String("tatooine")},
+					"installation_id": {N: aws.String("2")},
+				}),
+			},
+			queryErr: nil,
+			output: installConfig{
+				AppID:         1,
+				WebhookSecret: "skywalker",
+			},
+			err: "",
+		},
+	}
+
+	for _, test := range tests {
+		client := &mockDBClient{
+			queryOutputs: test.queryOutputs,
+			queryErr:     test.queryErr,
+		}
+		db := db{
+			dynamodb: client,
+		}
+
+		output, err := db.Get(test.key)
+
+		if (err != nil || test.err != "") && (err == nil || err.Error() != test.err) {
+			t.Errorf("description: %s, error received: %v, expected: %s", test.desc, err, test.err)
+		}
+
+		if output != test.output {
+			t.Errorf("description: %s, output received: %+v, expected: %+v", test.desc, output, test.output)
+		}
+
+		if !reflect.DeepEqual(client.updatedTables, test.updated) {
+			t.Errorf("description: %s, updated tables received: %v, expected: %v", test.desc, client.updatedTables, test.updated)
+		}
+	}
+}
+
+func TestPutConfig(t *testing.T) {
+	tests := []struct {
+		desc          string
+		config        cachedConfig
+		updateItemErr error
+		err           string
+	}{
+		{
+			desc: "error updating item",
+			config: cachedConfig{
+				Path:    "jedi/archives/.heupr.yml",
+				Content: "backends: []",
+			},
+			updateItemErr: errors.New("mock update error"),
+			err:           "put config item error: mock update error",
+		},
+		{
+			desc: "successful invocation",
+			config: cachedConfig{
+				Path:    "jedi/archives/.heupr.yml",
+				Missing: true,
+				Expires: time.Unix(1138, 0),
+			},
+			updateItemErr: nil,
+			err:           "",
+		},
+	}
+
+	for _, test := range tests {
+		db := db{
+			dynamodb: &mockDBClient{
+				updateItemErr: test.updateItemErr,
+			},
+		}
+
+		err := db.PutConfig(test.config)
+		if err != nil && err.Error() != test.err {
+			t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
+		}
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	tests := []struct {
+		desc            string
+		queryItemOutput *dynamodb.QueryOutput
+		queryErr        error
+		output          cachedConfig
+		err             string
+	}{
+		{
+			desc:            "error getting item",
+			queryItemOutput: nil,
+			queryErr:        errors.New("query mock error"),
+			output:          cachedConfig{Path: "jedi/archives/.heupr.yml"},
+			err:             "get config item error: query mock error",
+		},
+		{
+			desc: "config not cached",
+			queryItemOutput: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{},
+			},
+			queryErr: nil,
+			output:   cachedConfig{Path: "jedi/archives/.heupr.yml"},
+			err:      errNotCached.Error(),
+		},
+		{
+			desc: "successful invocation",
 			queryItemOutput: &dynamodb.QueryOutput{
 				Items: []map[string]*dynamodb.AttributeValue{
 					map[string]*dynamodb.AttributeValue{
-						"app_id": {
-							N: aws.String("1"),
+						"config_path": {
+							S: aws.String("jedi/archives/.heupr.yml"),
 						},
-						"pem": {
-							S: aws.String("tatoo-i-tatoo-ii-ghomrassen-guermessa-chenini"),
+						"file_content": {
+							S: aws.String("backends: []"),
 						},
-						"webhook_secret": {
-							S: aws.String("skywalker"),
+						"file_missing": {
+							BOOL: aws.Bool(false),
 						},
-						"installation_id": {
-							N: aws.String("2"),
-						},
-						"full_name": {
-							S: aws.String("tatooine"),
+						"expires_at": {
+							N: aws.String("1138"),
 						},
 					},
 				},
 			},
 			queryErr: nil,
-			output: installConfig{
-				AppID:          1,
-				PEM:            "tatoo-i-tatoo-ii-ghomrassen-guermessa-chenini",
-				WebhookSecret:  "skywalker",
-				InstallationID: 2,
+			output: cachedConfig{
+				Path:    "jedi/archives/.heupr.yml",
+				Content: "backends: []",
+				Expires: time.Unix(1138, 0),
 			},
 			err: "",
 		},
@@ -203,14 +666,90 @@ func TestGet(t *testing.T) {
 			},
 		}
 
-		output, err := db.Get(test.key)
-
+		output, err := db.GetConfig("jedi/archives/.heupr.yml")
 		if err != nil && err.Error() != test.err {
 			t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
 		}
 
-		if output.PEM != test.output.PEM {
+		if output != test.output {
 			t.Errorf("description: %s, output received: %+v, expected: %+v", test.desc, output, test.output)
 		}
 	}
 }
+
+func TestPutDelivery(t *testing.T) {
+	tests := []struct {
+		desc          string
+		updateItemErr error
+		recorded      bool
+		err           string
+	}{
+		{
+			desc:          "error updating item",
+			updateItemErr: errors.New("mock update error"),
+			recorded:      false,
+			err:           "put delivery item error: mock update error",
+		},
+		{
+			desc:          "delivery already recorded",
+			updateItemErr: awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "mock condition error", nil),
+			recorded:      false,
+			err:           "",
+		},
+		{
+			desc:          "successful invocation",
+			updateItemErr: nil,
+			recorded:      true,
+			err:           "",
+		},
+	}
+
+	for _, test := range tests {
+		db := db{
+			dynamodb: &mockDBClient{
+				updateItemErr: test.updateItemErr,
+			},
+		}
+
+		recorded, err := db.PutDelivery("order-66", time.Now().Add(time.Hour))
+		if err != nil && err.Error() != test.err {
+			t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
+		}
+
+		if recorded != test.recorded {
+			t.Errorf("description: %s, recorded received: %t, expected: %t", test.desc, recorded, test.recorded)
+		}
+	}
+}
+
+func TestDeleteDelivery(t *testing.T) {
+	tests := []struct {
+		desc          string
+		deleteItemErr error
+		err           string
+	}{
+		{
+			desc:          "error deleting item",
+			deleteItemErr: errors.New("mock delete error"),
+			err:           "delete delivery item error: mock delete error",
+		},
+		{
+			desc:          "successful invocation",
+			deleteItemErr: nil,
+			err:           "",
+		},
+	}
+
+	for _, test := range tests {
+		db := db{
+			dynamodb: &mockDBClient{
+				deleteItemErr: test.deleteItemErr,
+			},
+		}
+
+		err := db.DeleteDelivery("order-66")
+		if (err == nil && test.err != "") || (err != nil && err.Error() != test.err) {
+			t.Errorf("description: %s, error received: %v, expected: %s", test.desc, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package frontend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v28/github"
+	"github.com/tidwall/gjson"
+
+	"github.com/heupr/heupr/config"
+)
+
+// dryRunAll enables dry runs for every repository regardless of its config
+var dryRunAll, _ = strconv.ParseBool(os.Getenv("HEUPR_DRY_RUN"))
+
+// recorder captures the write requests made through a dry run client
+type recorder struct {
+	client *github.Client
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if readOnly(req.Method) {
+		return r.forward(req)
+	}
+
+	call, err := newCall(req)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("dry run: [%s] %s %s %s\n", call.Backend, call.Method, call.Path, call.Body)
+
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+
+	return jsonResponse(req, http.StatusOK, "null"), nil
+}
+
+// readOnly reports whether requests with the method leave GitHub unchanged
+func readOnly(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// forward sends the request through the wrapped client and returns its raw response
+func (r *recorder) forward(req *http.Request) (*http.Response, error) {
+	body := &bytes.Buffer{}
+	resp, err := r.client.Do(req.Context(), req, body)
+	if resp == nil || resp.Response == nil {
+		return nil, err
+	}
+
+	switch e := err.(type) {
+	case nil:
+	case *github.AcceptedError:
+		body = bytes.NewBuffer(e.Raw)
+	case *github.ErrorResponse:
+		content, _ := json.Marshal(e)
+		body = bytes.NewBuffer(content)
+	default:
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		Body:       ioutil.NopCloser(body),
+		Request:    req,
+	}, nil
+}
+
+// captured returns the write requests recorded so far
+func (r *recorder) captured() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Call{}, r.calls...)
+}
+
+// dryRun wraps the client if dry runs are enabled globally or by the config
+func dryRun(client *github.Client, cfg *config.Config) (*github.Client, *recorder) {
+	if !dryRunAll && (cfg.DryRun == nil || !cfg.DryRun.Enabled) {
+		return client, nil
+	}
+
+	rec := &recorder{
+		client: client,
+	}
+
+	output := github.NewClient(&http.Client{Transport: rec})
+	output.BaseURL = client.BaseURL
+	output.UploadURL = client.UploadURL
+
+	return output, rec
+}
+
+// dryRunSummary describes the recorded requests as a comment body
+func dryRunSummary(calls []Call) string {
+	lines := []string{"Heupr is running in dry-run mode and did not make the following changes:", ""}
+	for _, call := range calls {
+		line := fmt.Sprintf("- `%s`: `%s %s`", call.Backend, call.Method, call.Path)
+		if call.Body != "" {
+			line += fmt.Sprintf(" `%s`", call.Body)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// finishDryRun returns the requests recorded for the repo, posting the summary comment if configured
+func finishDryRun(client *github.Client, cfg *config.Config, rec *recorder, fullName, body string) []Call {
+	if rec == nil {
+		return nil
+	}
+
+	calls := rec.captured()
+	if len(calls) == 0 || cfg.DryRun == nil || !cfg.DryRun.Comment {
+		return calls
+	}
+
+	number := gjson.Get(body, "issue.number").Int()
+	if number == 0 {
+		number = gjson.Get(body, "pull_request.number").Int()
+	}
+	if number == 0 {
+		log.Printf("no issue or pull request for dry run summary on %s\n", fullName)
+		return calls
+	}
+
+	fullNameSplit := strings.Split(fullName, "/")
+	if err := createComment(client, fullNameSplit[0], fullNameSplit[1], int(number), dryRunSummary(calls)); err != nil {
+		log.Printf("error posting dry run summary on %s: %s\n", fullName, err.Error())
+	}
+
+	return calls
+}
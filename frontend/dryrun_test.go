@@ -0,0 +1,196 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+
+	"github.com/heupr/heupr/config"
+)
+
+func Test_dryRun(t *testing.T) {
+	defer saveStubs()()
+
+	tests := []struct {
+		desc    string
+		all     bool
+		dryRun  *config.DryRun
+		wrapped bool
+	}{
+		{
+			desc:    "no dry run block",
+			all:     false,
+			dryRun:  nil,
+			wrapped: false,
+		},
+		{
+			desc:    "dry run disabled",
+			all:     false,
+			dryRun:  &config.DryRun{Enabled: false},
+			wrapped: false,
+		},
+		{
+			desc:    "dry run enabled by config",
+			all:     false,
+			dryRun:  &config.DryRun{Enabled: true},
+			wrapped: true,
+		},
+		{
+			desc:    "dry run enabled globally",
+			all:     true,
+			dryRun:  nil,
+			wrapped: true,
+		},
+	}
+
+	for _, test := range tests {
+		dryRunAll = test.all
+
+		client := github.NewClient(nil)
+		output, rec := dryRun(client, &config.Config{DryRun: test.dryRun})
+		if wrapped := output != client && rec != nil; wrapped != test.wrapped {
+			t.Errorf("description: %s, wrapped received: %t, expected: %t", test.desc, wrapped, test.wrapped)
+		}
+	}
+}
+
+func Test_recorder(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/repos/jedi/temple/issues/66":
+			w.Write([]byte(`{"number":66,"title":"execute order 66"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	wrapped, rec := dryRun(client, &config.Config{DryRun: &config.DryRun{Enabled: true}})
+	ctx := context.WithValue(context.Background(), backendKey{}, "assignissue")
+
+	issue, _, err := wrapped.Issues.Get(ctx, "jedi", "temple", 66)
+	if err != nil || issue.GetTitle() != "execute order 66" {
+		t.Errorf("description: read request not forwarded, received: %+v, error: %v", issue, err)
+	}
+
+	if _, resp, err := wrapped.Issues.Get(ctx, "sith", "temple", 66); err == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("description: read error not forwarded, error: %v", err)
+	}
+
+	if _, _, err := wrapped.Issues.AddAssignees(ctx, "jedi", "temple", 66, []string{"yoda"}); err != nil {
+		t.Errorf("description: unexpected write error: %s", err.Error())
+	}
+
+	expectedRequests := []string{"GET /repos/jedi/temple/issues/66", "GET /repos/sith/temple/issues/66"}
+	if !reflect.DeepEqual(requests, expectedRequests) {
+		t.Errorf("description: incorrect requests sent, received: %v, expected: %v", requests, expectedRequests)
+	}
+
+	expectedCalls := []Call{
+		{
+			Backend: "assignissue",
+			Method:  "POST",
+			Path:    "/repos/jedi/temple/issues/66/assignees",
+			Body:    `{"assignees":["yoda"]}`,
+		},
+	}
+	if calls := rec.captured(); !reflect.DeepEqual(calls, expectedCalls) {
+		t.Errorf("description: incorrect calls recorded, received: %+v, expected: %+v", calls, expectedCalls)
+	}
+}
+
+func Test_finishDryRun(t *testing.T) {
+	defer saveStubs()()
+
+	calls := []Call{
+		{
+			Backend: "estimatepr",
+			Method:  "POST",
+			Path:    "/repos/jedi/temple/issues/66/comments",
+			Body:    `{"body":"estimate: 2"}`,
+		},
+	}
+
+	tests := []struct {
+		desc       string
+		rec        *recorder
+		dryRun     *config.DryRun
+		body       string
+		commentErr error
+		comment    string
+		calls      []Call
+	}{
+		{
+			desc:   "dry run disabled",
+			rec:    nil,
+			dryRun: nil,
+			body:   `{"pull_request":{"number":66}}`,
+			calls:  nil,
+		},
+		{
+			desc:   "summary comment not configured",
+			rec:    &recorder{calls: calls},
+			dryRun: &config.DryRun{Enabled: true},
+			body:   `{"pull_request":{"number":66}}`,
+			calls:  calls,
+		},
+		{
+			desc:   "event without issue or pull request",
+			rec:    &recorder{calls: calls},
+			dryRun: &config.DryRun{Enabled: true, Comment: true},
+			body:   `{"ref":"refs/heads/master"}`,
+			calls:  calls,
+		},
+		{
+			desc:       "error posting summary comment",
+			rec:        &recorder{calls: calls},
+			dryRun:     &config.DryRun{Enabled: true, Comment: true},
+			body:       `{"pull_request":{"number":66}}`,
+			commentErr: errors.New("mock comment error"),
+			comment:    "jedi/temple#66",
+			calls:      calls,
+		},
+		{
+			desc:    "summary comment posted",
+			rec:     &recorder{calls: calls},
+			dryRun:  &config.DryRun{Enabled: true, Comment: true},
+			body:    `{"pull_request":{"number":66}}`,
+			comment: "jedi/temple#66",
+			calls:   calls,
+		},
+	}
+
+	for _, test := range tests {
+		comment := ""
+		createComment = func(c *github.Client, owner, repo string, number int, body string) error {
+			comment = fmt.Sprintf("%s/%s#%d", owner, repo, number)
+			if !strings.Contains(body, "- `estimatepr`: `POST /repos/jedi/temple/issues/66/comments` `{\"body\":\"estimate: 2\"}`") {
+				t.Errorf("description: %s, incorrect summary comment: %s", test.desc, body)
+			}
+			return test.commentErr
+		}
+
+		received := finishDryRun(github.NewClient(nil), &config.Config{DryRun: test.dryRun}, test.rec, "jedi/temple", test.body)
+		if !reflect.DeepEqual(received, test.calls) {
+			t.Errorf("description: %s, calls received: %+v, expected: %+v", test.desc, received, test.calls)
+		}
+
+		if comment != test.comment {
+			t.Errorf("description: %s, comment received: %s, expected: %s", test.desc, comment, test.comment)
+		}
+	}
+}
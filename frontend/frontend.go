@@ -21,19 +21,27 @@ var newClient = func(appID, installationID int64, file string) (*github.Client,
 	}), nil
 }
 
-var getContent = func(c *github.Client, owner, repo, path string) (string, error) {
-	opts := &github.RepositoryContentGetOptions{}
-	file, _, _, err := c.Repositories.GetContents(context.Background(), owner, repo, path, opts)
+// errNotFound is returned by getContent when the requested file does not exist
+var errNotFound = errors.New("content not found")
+
+var getContent = func(c *github.Client, owner, repo, path, ref string) (string, string, error) {
+	opts := &github.RepositoryContentGetOptions{
+		Ref: ref,
+	}
+	file, _, resp, err := c.Repositories.GetContents(context.Background(), owner, repo, path, opts)
 	if err != nil {
-		return "", errors.New("error getting content object: " + err.Error())
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", "", errNotFound
+		}
+		return "", "", errors.New("error getting content object: " + err.Error())
 	}
 
 	content, err := file.GetContent()
 	if err != nil {
-		return "", errors.New("error getting content string: " + err.Error())
+		return "", "", errors.New("error getting content string: " + err.Error())
 	}
 
-	return content, nil
+	return content, file.GetSHA(), nil
 }
 
 var validateEvent = func(secret, signature string, body []byte) error {
@@ -44,3 +52,19 @@ var validateEvent = func(secret, signature string, body []byte) error {
 
 	return nil
 }
+
+var createCheckRun = func(c *github.Client, owner, repo string, opts github.CreateCheckRunOptions) error {
+	if _, _, err := c.Checks.CreateCheckRun(context.Background(), owner, repo, opts); err != nil {
+		return errors.New("error creating check run: " + err.Error())
+	}
+
+	return nil
+}
+
+var createComment = func(c *github.Client, owner, repo string, number int, body string) error {
+	if _, _, err := c.Issues.CreateComment(context.Background(), owner, repo, number, &github.IssueComment{Body: &body}); err != nil {
+		return errors.New("error creating comment: " + err.Error())
+	}
+
+	return nil
+}
@@ -19,9 +19,20 @@ import (
 
 func TestMain(m *testing.M) {
 	log.SetOutput(ioutil.Discard)
+	defaultConfig = ""
 	os.Exit(m.Run())
 }
 
+// saveStubs returns a function restoring the package values tests replace
+func saveStubs() func() {
+	client, content, validate, checkRun, comment, send := newClient, getContent, validateEvent, createCheckRun, createComment, post
+	config, all, timeout := defaultConfig, dryRunAll, backendTimeout
+	return func() {
+		newClient, getContent, validateEvent, createCheckRun, createComment, post = client, content, validate, checkRun, comment, send
+		defaultConfig, dryRunAll, backendTimeout = config, all, timeout
+	}
+}
+
 func Test_newClient(t *testing.T) {
 	mux := http.NewServeMux()
 
@@ -36,7 +47,7 @@ func Test_newClient(t *testing.T) {
 	c.BaseURL = url
 	c.UploadURL = url
 
-	output, err := getContent(c, "temple", "archives", "kamino.txt")
+	output, _, err := getContent(c, "temple", "archives", "kamino.txt", "")
 	if output == "" {
 		t.Errorf("description: error getting file contents, received: %s", output)
 	}
@@ -44,6 +55,38 @@ func Test_newClient(t *testing.T) {
 	if err != nil {
 		t.Errorf("description: error getting file contents, error: %s", err.Error())
 	}
+
+	if _, _, err := getContent(c, "temple", "archives", "missing.txt", ""); err != errNotFound {
+		t.Errorf("description: error getting missing file, received: %v, expected: %s", err, errNotFound)
+	}
+}
+
+func Test_createCheckRun(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/repos/temple/archives/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":66}`)
+	})
+
+	server := httptest.NewServer(mux)
+
+	c := github.NewClient(nil)
+	url, _ := url.Parse(server.URL + "/")
+	c.BaseURL = url
+	c.UploadURL = url
+
+	opts := github.CreateCheckRunOptions{
+		Name:    "heupr config",
+		HeadSHA: "order-66",
+	}
+
+	if err := createCheckRun(c, "temple", "archives", opts); err != nil {
+		t.Errorf("description: error creating check run, error: %s", err.Error())
+	}
+
+	if err := createCheckRun(c, "temple", "records", opts); err == nil {
+		t.Errorf("description: error creating check run, expected error for unknown repo")
+	}
 }
 
 func stringEvent(event interface{}) string {
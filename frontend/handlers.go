@@ -2,34 +2,160 @@ package frontend
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/go-github/v28/github"
 	"github.com/tidwall/gjson"
+	"gopkg.in/yaml.v3"
 
 	"github.com/heupr/heupr/backend"
+	"github.com/heupr/heupr/config"
 )
 
+// Result describes the outcome of a handler invocation
+type Result struct {
+	Status       int          `json:"-"`
+	Code         string       `json:"code,omitempty"`
+	Message      string       `json:"message"`
+	Repositories []RepoResult `json:"repositories,omitempty"`
+}
+
+// RepoResult describes the backends invoked on behalf of a single repository
+type RepoResult struct {
+	Repository   string          `json:"repository"`
+	ConfigSource string          `json:"config_source,omitempty"`
+	Skipped      string          `json:"skipped,omitempty"`
+	Backends     []BackendResult `json:"backends"`
+	DryRun       []Call          `json:"dry_run,omitempty"`
+}
+
+// BackendResult describes the outcome of a single backend invocation
+type BackendResult struct {
+	Backend   string `json:"backend"`
+	Succeeded bool   `json:"succeeded"`
+	Error     string `json:"error,omitempty"`
+}
+
+// invoked reports whether any backend was invoked
+func (r *Result) invoked() bool {
+	for _, repo := range r.Repositories {
+		if len(repo.Backends) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// failures reports every failed backend invocation alongside every successful one
+func (r *Result) failures() (failed, succeeded []string) {
+	for _, repo := range r.Repositories {
+		for _, bknd := range repo.Backends {
+			if !bknd.Succeeded {
+				failed = append(failed, fmt.Sprintf("%s (%s): %s", bknd.Backend, repo.Repository, bknd.Error))
+				continue
+			}
+			succeeded = append(succeeded, fmt.Sprintf("%s (%s)", bknd.Backend, repo.Repository))
+		}
+	}
+	return failed, succeeded
+}
+
 // APIResponse generates required output for proxy integrations
-func APIResponse(code int, msg string) (events.APIGatewayProxyResponse, error) {
-	log.Printf("response code: %d, message: %s\n", code, msg)
-	resp := events.APIGatewayProxyResponse{
-		StatusCode:      code,
-		Body:            msg,
+func APIResponse(result Result) (events.APIGatewayProxyResponse, error) {
+	log.Printf("response code: %d, error code: %s, message: %s\n", result.Status, result.Code, result.Message)
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+			Body:       "error encoding response: " + err.Error(),
+		}, nil
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: result.Status,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body:            string(body),
 		IsBase64Encoded: false,
+	}, nil
+}
+
+// Error codes reported in the body of failed responses
+const (
+	codeBadRequest          = "bad_request"
+	codeInvalidSignature    = "invalid_signature"
+	codeUnknownInstallation = "unknown_installation"
+	codeInvalidConfig       = "invalid_config"
+	codeBackendFailure      = "backend_failure"
+	codeInternal            = "internal_error"
+)
+
+// apiError describes a failed request with the status and error code reported to the caller
+type apiError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newAPIError(status int, code, msg string) *apiError {
+	return &apiError{
+		Status:  status,
+		Code:    code,
+		Message: msg,
 	}
+}
+
+func (e *apiError) Error() string {
+	return e.Message
+}
+
+// errorResult describes a failed request
+func errorResult(err error) Result {
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		apiErr = newAPIError(http.StatusInternalServerError, codeInternal, err.Error())
+	}
+
+	return Result{
+		Status:  apiErr.Status,
+		Code:    apiErr.Code,
+		Message: apiErr.Message,
+	}
+}
 
-	if msg == "success" || msg == "query already exists" {
-		return resp, nil
+// errorResponse generates a JSON error body for proxy integrations
+func errorResponse(err error) (events.APIGatewayProxyResponse, error) {
+	return APIResponse(errorResult(err))
+}
+
+// installError reports a failed installation lookup, distinguishing unknown installations
+func installError(err error) *apiError {
+	if err == errNotInstalled {
+		return newAPIError(http.StatusNotFound, codeUnknownInstallation, "error getting config: "+err.Error())
 	}
+	return newAPIError(http.StatusInternalServerError, codeInternal, "error getting config: "+err.Error())
+}
 
-	return resp, errors.New(msg)
+// acknowledge generates a 2xx output for events received successfully but not processed
+func acknowledge(code int, reason string) (events.APIGatewayProxyResponse, error) {
+	return APIResponse(Result{
+		Status:  code,
+		Message: reason,
+	})
 }
 
 type installConfig struct {
@@ -51,20 +177,20 @@ func Install(request events.APIGatewayProxyRequest, db Database) (events.APIGate
 
 	code := request.QueryStringParameters["code"]
 	if code == "" {
-		return APIResponse(http.StatusBadRequest, "no code received")
+		return errorResponse(newAPIError(http.StatusBadRequest, codeBadRequest, "no code received"))
 	}
 	log.Printf("request code: %s\n", code)
 
 	b := new(bytes.Buffer)
 	req, err := http.NewRequest("POST", "https://api.github.com/app-manifests/"+code+"/conversions", b)
 	if err != nil {
-		return APIResponse(http.StatusInternalServerError, "error creating response: "+err.Error())
+		return errorResponse(errors.New("error creating response: " + err.Error()))
 	}
 	req.Header.Set("Accept", "application/vnd.github.fury-preview+json")
 
 	resp, err := post(req)
 	if err != nil {
-		return APIResponse(http.StatusInternalServerError, "error converting code: "+err.Error())
+		return errorResponse(errors.New("error converting code: " + err.Error()))
 	}
 	defer resp.Body.Close()
 
@@ -72,18 +198,18 @@ func Install(request events.APIGatewayProxyRequest, db Database) (events.APIGate
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return APIResponse(http.StatusInternalServerError, "error reading conversion body")
+		return errorResponse(errors.New("error reading conversion body"))
 	}
 	log.Printf("request body: %s\n", body)
 
 	config := installConfig{}
 	if err := json.Unmarshal(body, &config); err != nil {
-		return APIResponse(http.StatusInternalServerError, "error parsing conversion body")
+		return errorResponse(errors.New("error parsing conversion body"))
 	}
 	log.Printf("installation config: %+v\n", config)
 
 	if err := db.Put(config); err != nil {
-		return APIResponse(http.StatusInternalServerError, "error putting app config: "+err.Error())
+		return errorResponse(errors.New("error putting app config: " + err.Error()))
 	}
 
 	log.Println("successful install handler invocation")
@@ -98,25 +224,385 @@ func Install(request events.APIGatewayProxyRequest, db Database) (events.APIGate
 	}, nil
 }
 
-type webhookEvent struct {
-	Name    string   `yaml:"name"`
-	Actions []string `yaml:"actions"`
+// schemas collects the settings types of the backends, nil for those that failed to load, for config validation
+func schemas(bknds *backend.Registry) map[string]interface{} {
+	output := make(map[string]interface{})
+	for name := range bknds.Factories() {
+		output[name] = bknds.Settings(name)
+	}
+	return output
+}
+
+// declaredEvents collects the event types declared in the manifests of the available backends
+func declaredEvents(bknds *backend.Registry) map[string]bool {
+	output := make(map[string]bool)
+	for _, name := range bknds.Names() {
+		m, _ := bknds.Manifest(name)
+		for _, event := range m.Events {
+			output[event] = true
+		}
+	}
+	return output
+}
+
+// builtinConfig is the default config, which invokes no backends, used unless HEUPR_DEFAULT_CONFIG is set
+const builtinConfig = "backends: []\n"
+
+// defaultConfig is used for repositories without a repo or organization config file
+var defaultConfig = stringEnv("HEUPR_DEFAULT_CONFIG", builtinConfig)
+
+func stringEnv(key, fallback string) string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	return value
+}
+
+// configFile is the content of a .heupr.yml file and the location it was loaded from
+type configFile struct {
+	Content string
+	Source  string
+}
+
+// configPath returns the cache key of the .heupr.yml file in the repo
+func configPath(owner, repo string) string {
+	return owner + "/" + repo + "/.heupr.yml"
+}
+
+// configTTL is how long a cached .heupr.yml file is used before it is fetched again
+var configTTL = durationEnv("HEUPR_CONFIG_TTL", time.Hour)
+
+// cachedContent retrieves the .heupr.yml file in the repo, preferring the cached file unless refreshing
+func cachedContent(client *github.Client, db Database, owner, repo string, refresh bool) (string, error) {
+	path := configPath(owner, repo)
+	if !refresh {
+		cached, err := db.GetConfig(path)
+		if err == nil && time.Now().Before(cached.Expires) {
+			if cached.Missing {
+				return "", errNotFound
+			}
+			return cached.Content, nil
+		} else if err != nil && err != errNotCached {
+			log.Printf("error getting cached config %s: %s\n", path, err.Error())
+		}
+	}
+
+	file, _, err := getContent(client, owner, repo, ".heupr.yml", "")
+	if err != nil && err != errNotFound {
+		return "", err
+	}
+
+	if putErr := db.PutConfig(cachedConfig{
+		Path:    path,
+		Content: file,
+		Missing: err == errNotFound,
+		Expires: time.Now().Add(configTTL),
+	}); putErr != nil {
+		log.Printf("error caching config %s: %s\n", path, putErr.Error())
+	}
+
+	return file, err
+}
+
+// resolveConfig retrieves the owner's .github config and the repo config, or the default config
+func resolveConfig(client *github.Client, db Database, owner, repo string, refresh bool) ([]configFile, error) {
+	repos := []string{repo}
+	if repo != ".github" {
+		repos = []string{".github", repo}
+	}
+
+	files := []configFile{}
+	for _, name := range repos {
+		file, err := cachedContent(client, db, owner, name, refresh)
+		if err == errNotFound {
+			log.Printf("no config file found in %s/%s\n", owner, name)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		files = append(files, configFile{
+			Content: file,
+			Source:  configPath(owner, name),
+		})
+	}
+
+	if len(files) == 0 && defaultConfig != "" {
+		files = append(files, configFile{
+			Content: defaultConfig,
+			Source:  "default",
+		})
+	}
+
+	return files, nil
+}
+
+// mergeConfig validates the config files and merges each on top of the previous one
+func mergeConfig(files []configFile, bknds *backend.Registry) (*config.Config, []byte, string, error) {
+	var cfg *config.Config
+	sources := []string{}
+	for i, file := range files {
+		fileCfg, err := loadConfig(file.Content, bknds)
+		if err != nil {
+			if i < len(files)-1 {
+				return nil, nil, "", fmt.Errorf("%s: %s", file.Source, err.Error())
+			}
+			return nil, nil, "", err
+		}
+
+		if cfg == nil {
+			cfg = fileCfg
+		} else {
+			cfg = config.Merge(cfg, fileCfg)
+		}
+		sources = append(sources, file.Source)
+	}
+
+	if len(files) == 1 {
+		return cfg, []byte(files[0].Content), files[0].Source, nil
+	}
+
+	content, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return cfg, content, strings.Join(sources, " + "), nil
+}
+
+// loadConfig parses and validates the repository .heupr.yml file content
+func loadConfig(file string, bknds *backend.Registry) (*config.Config, error) {
+	cfg, err := config.Parse([]byte(file))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(schemas(bknds), declaredEvents(bknds)); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// configChanged reports whether any commit in a push event body adds, modifies, or removes the .heupr.yml file
+func configChanged(body string) bool {
+	if gjson.Get(body, "deleted").Bool() {
+		return false
+	}
+
+	changed := false
+	gjson.Get(body, "commits").ForEach(func(_, commit gjson.Result) bool {
+		for _, key := range []string{"added", "modified", "removed"} {
+			for _, file := range commit.Get(key).Array() {
+				if file.String() == ".heupr.yml" {
+					changed = true
+					return false
+				}
+			}
+		}
+		return true
+	})
+
+	return changed
+}
+
+// checkConfig refreshes the cached config on pushes changing .heupr.yml and reports a check run
+func checkConfig(client *github.Client, db Database, bknds *backend.Registry, owner, repo, body string) error {
+	headSHA := gjson.Get(body, "after").String()
+
+	file, sha, err := getContent(client, owner, repo, ".heupr.yml", headSHA)
+	if err != nil && err != errNotFound {
+		return errors.New("error getting repo config file: " + err.Error())
+	}
+	log.Printf("file sha: %s, content: %s\n", sha, file)
+
+	if defaultBranch(body) {
+		if err := db.PutConfig(cachedConfig{
+			Path:    configPath(owner, repo),
+			Content: file,
+			Missing: err == errNotFound,
+			Expires: time.Now().Add(configTTL),
+		}); err != nil {
+			return errors.New("error caching repo config file: " + err.Error())
+		}
+	}
+
+	if err == errNotFound {
+		log.Printf("repo config file removed in push to %s/%s\n", owner, repo)
+		return nil
+	}
+
+	if err := createCheckRun(client, owner, repo, configCheck(file, headSHA, bknds)); err != nil {
+		log.Printf("error reporting config check for %s/%s: %s\n", owner, repo, err.Error())
+	}
+
+	return nil
+}
+
+// defaultBranch reports whether a push event body targets the repository default branch
+func defaultBranch(body string) bool {
+	return gjson.Get(body, "ref").String() == "refs/heads/"+gjson.Get(body, "repository.default_branch").String()
+}
+
+// maxAnnotations is the number of annotations GitHub accepts per check run request
+const maxAnnotations = 50
+
+// configCheck builds a completed check run reporting the validation results of the .heupr.yml file
+func configCheck(file, headSHA string, bknds *backend.Registry) github.CreateCheckRunOptions {
+	opts := github.CreateCheckRunOptions{
+		Name:        "heupr config",
+		HeadSHA:     headSHA,
+		Status:      github.String("completed"),
+		CompletedAt: &github.Timestamp{Time: time.Now()},
+	}
+
+	_, err := loadConfig(file, bknds)
+	if err == nil {
+		opts.Conclusion = github.String("success")
+		opts.Output = &github.CheckRunOutput{
+			Title:   github.String("Configuration valid"),
+			Summary: github.String("The .heupr.yml file is valid."),
+		}
+		return opts
+	}
+
+	errs, ok := err.(config.Errors)
+	if !ok {
+		errs = config.Errors{{Message: err.Error()}}
+	}
+
+	annotations := []*github.CheckRunAnnotation{}
+	for _, configErr := range errs {
+		if len(annotations) == maxAnnotations {
+			break
+		}
+
+		line := configErr.Line
+		if line == 0 {
+			line = 1
+		}
+
+		annotations = append(annotations, &github.CheckRunAnnotation{
+			Path:            github.String(".heupr.yml"),
+			StartLine:       github.Int(line),
+			EndLine:         github.Int(line),
+			AnnotationLevel: github.String("failure"),
+			Message:         github.String(configErr.Message),
+		})
+	}
+
+	opts.Conclusion = github.String("failure")
+	opts.Output = &github.CheckRunOutput{
+		Title:       github.String("Configuration invalid"),
+		Summary:     github.String(fmt.Sprintf("%d problem(s) found in the .heupr.yml file.", len(errs))),
+		Annotations: annotations,
+	}
+	return opts
+}
+
+// eventActions returns the action names a webhook event body satisfies
+func eventActions(eventType, body string) []string {
+	action := gjson.Get(body, "action").String()
+	actions := []string{action}
+
+	// NOTE: GitHub reports merges as "closed" pull requests so "merged" is derived
+	if eventType == "pull_request" && action == "closed" && gjson.Get(body, "pull_request.merged").Bool() {
+		actions = append(actions, "merged")
+	}
+
+	return actions
+}
+
+// subscribed reports whether the backend config lists the event type and action
+func subscribed(bkndConfig config.Backend, eventType, body string) bool {
+	if len(bkndConfig.Events) == 0 {
+		return true
+	}
+
+	for _, event := range bkndConfig.Events {
+		if event.Name != eventType {
+			continue
+		}
+
+		if len(event.Actions) == 0 {
+			return true
+		}
+
+		for _, action := range eventActions(eventType, body) {
+			for _, configAction := range event.Actions {
+				if action == configAction {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// eventParsers validates the payloads of event types with typed backend helpers
+var eventParsers = map[string]func(backend.Payload) error{
+	"issue_comment": func(p backend.Payload) error {
+		_, err := backend.IssueComment(p)
+		return err
+	},
+	"pull_request_review": func(p backend.Payload) error {
+		_, err := backend.PullRequestReview(p)
+		return err
+	},
+	"pull_request_review_comment": func(p backend.Payload) error {
+		_, err := backend.PullRequestReviewComment(p)
+		return err
+	},
 }
 
-type backendObj struct {
-	Name     string         `yaml:"name"`
-	Events   []webhookEvent `yaml:"events"`
-	Location string         `yaml:"location"`
+// supported reports whether the repository event type is handled
+func supported(eventType string, bknds *backend.Registry) bool {
+	if _, ok := eventParsers[eventType]; ok || eventType == "push" {
+		return true
+	}
+
+	for _, event := range backend.DefaultEvents {
+		if event == eventType {
+			return true
+		}
+	}
+
+	for _, name := range bknds.Names() {
+		if m, _ := bknds.Manifest(name); m.Handles(eventType) {
+			return true
+		}
+	}
+
+	return false
 }
 
-type configObj struct {
-	Backends []backendObj `yaml:"backends"`
+// enabled filters the available backends to those configured for the event
+func enabled(cfg *config.Config, bknds *backend.Registry, eventType, body string, install bool) map[string]backend.Factory {
+	output := make(map[string]backend.Factory)
+	for _, bkndConfig := range cfg.Backends {
+		factory := bknds.Factory(bkndConfig.Name)
+		if factory == nil {
+			log.Printf("backend %s not available\n", bkndConfig.Name)
+			continue
+		}
+
+		m, _ := bknds.Manifest(bkndConfig.Name)
+		if install || (m.Handles(eventType) && subscribed(bkndConfig, eventType, body)) {
+			output[bkndConfig.Name] = factory
+		}
+	}
+
+	return output
 }
 
 type payload struct {
-	B []byte
-	T string
-	C []byte
+	B   []byte
+	T   string
+	C   []byte
+	S   config.Backend
+	Src string
 }
 
 func (p *payload) Bytes() []byte {
@@ -131,122 +617,411 @@ func (p *payload) Config() []byte {
 	return p.C
 }
 
-// Event processes webhook events received by Heupr app repo installations
-func Event(request events.APIGatewayProxyRequest, db Database, bknds []backend.Backend) (events.APIGatewayProxyResponse, error) {
-	log.Printf("event request: %+v\n", request)
+func (p *payload) Settings(v interface{}) error {
+	return p.S.Decode(v)
+}
 
-	eventType := request.Headers["X-GitHub-Event"]
-	signature := request.Headers["X-Hub-Signature"]
-	log.Printf("event type: %s, signature: %s\n", eventType, signature)
+func (p *payload) ConfigSource() string {
+	return p.Src
+}
 
-	body := []byte(request.Body)
+// forBackend copies the payload with the settings block of the named backend
+func (p *payload) forBackend(cfg *config.Config, name string) *payload {
+	output := *p
+	output.S, _ = cfg.Backend(name)
+	return &output
+}
+
+// backendTimeout limits how long each backend may run for a single repository
+var backendTimeout = durationEnv("HEUPR_BACKEND_TIMEOUT", 4*time.Minute)
+
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// deliveryTTL is how long delivery IDs are kept to recognise redelivered events
+var deliveryTTL = durationEnv("HEUPR_DELIVERY_TTL", 72*time.Hour)
+
+// claimDelivery records the delivery ID, reporting false if it was already received
+func claimDelivery(db Database, deliveryID string) (bool, error) {
+	if deliveryID == "" {
+		return true, nil
+	}
+
+	claimed, err := db.PutDelivery(deliveryID, time.Now().Add(deliveryTTL))
+	if err != nil {
+		return false, errors.New("error recording delivery: " + err.Error())
+	}
+
+	return claimed, nil
+}
+
+// backendKey is the context key holding the name of the backend making a request
+type backendKey struct{}
+
+// backendAction is a single backend method call made on behalf of an event
+type backendAction func(ctx context.Context, req backend.Request, bknd backend.BackendV2) error
 
+// invoke runs the backend action and abandons it once the deadline passes
+func invoke(parent context.Context, req backend.Request, bknd backend.BackendV2, action backendAction) error {
+	ctx, cancel := context.WithTimeout(context.WithValue(parent, backendKey{}, req.Backend), backendTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("panic: %v", p)
+			}
+		}()
+		done <- action(ctx, req, bknd)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if parent.Err() != nil {
+			return errors.New("timed out at the worker deadline")
+		}
+		return fmt.Errorf("timed out after %s", backendTimeout)
+	}
+}
+
+// run invokes the action on a new instance of every backend concurrently, reporting each outcome
+func run(ctx context.Context, base backend.Request, bknds map[string]backend.Factory, action backendAction) []BackendResult {
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+	results := []BackendResult{}
+
+	for name, factory := range bknds {
+		wg.Add(1)
+		go func(name string, factory backend.Factory) {
+			defer wg.Done()
+
+			req := base
+			req.Backend = name
+			req.Logger = log.New(log.Writer(), fmt.Sprintf("[%s %s] ", name, base.DeliveryID), log.Flags())
+
+			result := BackendResult{
+				Backend:   name,
+				Succeeded: true,
+			}
+			if err := invoke(ctx, req, factory(), action); err != nil {
+				log.Printf("backend %s failed for %s: %s\n", name, base.Repo, err.Error())
+				result.Succeeded = false
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(name, factory)
+	}
+
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Backend < results[j].Backend
+	})
+
+	return results
+}
+
+// header retrieves a request header value regardless of key casing
+func header(headers map[string]string, key string) string {
+	if value, ok := headers[key]; ok {
+		return value
+	}
+
+	for k, value := range headers {
+		if strings.EqualFold(k, key) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// installationEvent reports whether the event type describes app installation changes
+func installationEvent(eventType string) bool {
 	switch eventType {
 	case "installation", "integration_installation", "installation_repositories", "integration_installation_repositories": // NOTE: Last two kept for GitHub inconsistency
-		appID := gjson.Get(request.Body, "installation.app_id").Int()
-		installationID := gjson.Get(request.Body, "installation.id").Int()
+		return true
+	}
+	return false
+}
 
-		log.Printf("app id: %d, installation id: %d\n", appID, installationID)
+// installedRepos lists the repositories named in an installation event body
+func installedRepos(eventType, body string) []string {
+	repos := gjson.Result{}
+	if !strings.Contains(eventType, "repositories") {
+		repos = gjson.Get(body, "repositories.#.full_name")
+	} else {
+		repos = gjson.Get(body, "repositories_added.#.full_name")
+	}
 
-		installConfig, err := db.Get(appID)
-		if err != nil {
-			return APIResponse(http.StatusInternalServerError, "error getting config: "+err.Error())
-		}
+	output := []string{}
+	for _, repo := range repos.Array() {
+		output = append(output, repo.String())
+	}
+	return output
+}
+
+// Event validates webhook events received by Heupr app repo installations and queues them for processing
+func Event(request events.APIGatewayProxyRequest, db Database, q Queue, bknds *backend.Registry) (resp events.APIGatewayProxyResponse, err error) {
+	log.Printf("event request: %+v\n", request)
 
-		if err := validateEvent(installConfig.WebhookSecret, signature, body); err != nil {
-			return APIResponse(http.StatusInternalServerError, "error validating event: "+err.Error())
+	eventType := header(request.Headers, "X-GitHub-Event")
+	signature := header(request.Headers, "X-Hub-Signature")
+	deliveryID := header(request.Headers, "X-GitHub-Delivery")
+	log.Printf("event type: %s, signature: %s, delivery id: %s\n", eventType, signature, deliveryID)
+
+	body := []byte(request.Body)
+	claimed := false
+	defer func() {
+		if !claimed || deliveryID == "" || resp.StatusCode < http.StatusMultipleChoices {
+			return
+		}
+		if err := db.DeleteDelivery(deliveryID); err != nil {
+			log.Printf("error releasing delivery %s: %s\n", deliveryID, err.Error())
 		}
+	}()
 
-		repos := gjson.Result{}
-		if !strings.Contains(eventType, "repositories") {
-			repos = gjson.Get(request.Body, "repositories.#.full_name")
-		} else {
-			repos = gjson.Get(request.Body, "repositories_added.#.full_name")
+	if eventType == "ping" {
+		log.Printf("ping zen: %s, hook id: %d\n", gjson.Get(request.Body, "zen").String(), gjson.Get(request.Body, "hook_id").Int())
+		return acknowledge(http.StatusOK, "pong")
+	}
+
+	install := installationEvent(eventType)
+	if !install && !supported(eventType, bknds) {
+		return acknowledge(http.StatusAccepted, fmt.Sprintf("event type %s not supported", eventType))
+	}
+
+	var key interface{} = gjson.Get(request.Body, "repository.full_name").String()
+	if install {
+		key = gjson.Get(request.Body, "installation.app_id").Int()
+	}
+
+	installConfig, err := db.Get(key)
+	if err != nil {
+		return errorResponse(installError(err))
+	}
+
+	if err := validateEvent(installConfig.WebhookSecret, signature, body); err != nil {
+		return errorResponse(newAPIError(http.StatusUnauthorized, codeInvalidSignature, "error validating event: "+err.Error()))
+	}
+
+	claimed, err = claimDelivery(db, deliveryID)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if !claimed {
+		return acknowledge(http.StatusOK, fmt.Sprintf("delivery %s already received", deliveryID))
+	}
+
+	if parse, ok := eventParsers[eventType]; ok {
+		if err := parse(&payload{T: eventType, B: body}); err != nil {
+			return errorResponse(newAPIError(http.StatusBadRequest, codeBadRequest, "error parsing event: "+err.Error()))
 		}
+	}
 
-		log.Printf("repositories: %v\n", repos)
+	if install {
+		inst := installation{
+			ID:    gjson.Get(request.Body, "installation.id").Int(),
+			AppID: installConfig.AppID,
+		}
+		if err := db.PutInstallation(inst); err != nil {
+			return errorResponse(errors.New("error putting installation: " + err.Error()))
+		}
 
-		for _, repo := range repos.Array() {
-			fullName := repo.String()
+		for _, fullName := range installedRepos(eventType, request.Body) {
 			log.Printf("repository: %s\n", fullName)
 
-			installConfig.FullName = fullName
-			installConfig.InstallationID = installationID
+			if err := db.PutRepo(repository{FullName: fullName, InstallationID: inst.ID}); err != nil {
+				return errorResponse(errors.New("error putting repository: " + err.Error()))
+			}
+		}
+	}
+
+	if err := q.Send(Delivery{
+		ID:       deliveryID,
+		Event:    eventType,
+		Body:     request.Body,
+		Received: time.Now(),
+	}); err != nil {
+		return errorResponse(errors.New("error queueing event: " + err.Error()))
+	}
 
-			client, err := newClient(installConfig.AppID, installConfig.InstallationID, installConfig.PEM)
+	log.Println("successful event handler invocation")
+	return acknowledge(http.StatusAccepted, "event queued")
+}
+
+// clientFunc creates the GitHub client used on behalf of an installation
+type clientFunc func(appID, installationID int64, file string) (*github.Client, error)
+
+// Process invokes the backends for a delivery queued by Event
+func Process(ctx context.Context, d Delivery, db Database, bknds *backend.Registry) Result {
+	return process(ctx, d, db, bknds, newClient)
+}
+
+func process(ctx context.Context, d Delivery, db Database, bknds *backend.Registry, connect clientFunc) Result {
+	log.Printf("process delivery: %s, event type: %s\n", d.ID, d.Event)
+
+	body := []byte(d.Body)
+	result := Result{
+		Status:  http.StatusOK,
+		Message: "success",
+	}
+
+	if installationEvent(d.Event) {
+		appID := gjson.Get(d.Body, "installation.app_id").Int()
+		installationID := gjson.Get(d.Body, "installation.id").Int()
+
+		log.Printf("app id: %d, installation id: %d\n", appID, installationID)
+
+		installConfig, err := db.Get(appID)
+		if err != nil {
+			return errorResult(installError(err))
+		}
+
+		client, err := connect(installConfig.AppID, installationID, installConfig.PEM)
+		if err != nil {
+			return errorResult(errors.New("error creating client: " + err.Error()))
+		}
+
+		for _, fullName := range installedRepos(d.Event, d.Body) {
+			log.Printf("repository: %s\n", fullName)
+
+			fullNameSplit := strings.Split(fullName, "/")
+			files, err := resolveConfig(client, db, fullNameSplit[0], fullNameSplit[1], true)
 			if err != nil {
-				return APIResponse(http.StatusInternalServerError, "error creating client: "+err.Error())
+				failed := errorResult(errors.New("error getting repo config file: " + err.Error()))
+				failed.Repositories = result.Repositories
+				return failed
 			}
 
-			if err := db.Put(installConfig); err != nil {
-				return APIResponse(http.StatusInternalServerError, "error putting app config: "+err.Error())
+			if len(files) == 0 {
+				log.Printf("no config available for %s\n", fullName)
+				result.Repositories = append(result.Repositories, RepoResult{
+					Repository: fullName,
+					Skipped:    "no config available",
+					Backends:   []BackendResult{},
+				})
+				continue
 			}
 
-			fullNameSplit := strings.Split(fullName, "/")
-			file, err := getContent(client, fullNameSplit[0], fullNameSplit[1], ".heupr.yml")
+			cfg, file, source, err := mergeConfig(files, bknds)
 			if err != nil {
-				return APIResponse(http.StatusInternalServerError, "error getting repo config file: "+err.Error())
+				failed := errorResult(newAPIError(http.StatusUnprocessableEntity, codeInvalidConfig, "error parsing repo config file: "+err.Error()))
+				failed.Repositories = result.Repositories
+				return failed
 			}
-			log.Printf("file content: %s\n", file)
+			log.Printf("file source: %s, content: %s\n", source, file)
 
 			backendPayload := &payload{
-				T: eventType,
-				B: body,
-				C: []byte(file),
+				T:   d.Event,
+				B:   body,
+				C:   file,
+				Src: source,
 			}
 
-			for _, bknd := range bknds {
-				bknd.Configure(client)
-				if err := bknd.Prepare(backendPayload); err != nil {
-					return APIResponse(http.StatusInternalServerError, "error calling backend prepare: "+err.Error())
-				}
+			req := backend.Request{
+				DeliveryID: d.ID,
+				Event:      d.Event,
+				Repo:       fullName,
 			}
-		}
 
-	case "issues", "pull_request", "project", "project_card", "project_column":
-		fullName := gjson.Get(request.Body, "repository.full_name").String()
+			bkndClient, rec := dryRun(client, cfg)
+			bkndResults := run(ctx, req, enabled(cfg, bknds, d.Event, d.Body, true), func(ctx context.Context, req backend.Request, bknd backend.BackendV2) error {
+				bknd.Configure(ctx, req, bkndClient)
+				return bknd.Prepare(ctx, req, backendPayload.forBackend(cfg, req.Backend))
+			})
+
+			result.Repositories = append(result.Repositories, RepoResult{
+				Repository:   fullName,
+				ConfigSource: source,
+				Backends:     bkndResults,
+				DryRun:       finishDryRun(client, cfg, rec, fullName, d.Body),
+			})
+		}
+	} else {
+		fullName := gjson.Get(d.Body, "repository.full_name").String()
 
 		installConfig, err := db.Get(fullName)
 		if err != nil {
-			return APIResponse(http.StatusInternalServerError, "error getting config: "+err.Error())
+			return errorResult(installError(err))
 		}
 		log.Printf("installation config: %+v\n", installConfig)
 
-		if err := validateEvent(installConfig.WebhookSecret, signature, body); err != nil {
-			return APIResponse(http.StatusInternalServerError, "error validating event: "+err.Error())
+		client, err := connect(installConfig.AppID, installConfig.InstallationID, installConfig.PEM)
+		if err != nil {
+			return errorResult(errors.New("error creating client: " + err.Error()))
+		}
+
+		fullNameSplit := strings.Split(fullName, "/")
+		if d.Event == "push" && configChanged(d.Body) {
+			if err := checkConfig(client, db, bknds, fullNameSplit[0], fullNameSplit[1], d.Body); err != nil {
+				return errorResult(err)
+			}
 		}
 
-		client, err := newClient(installConfig.AppID, installConfig.InstallationID, installConfig.PEM)
+		files, err := resolveConfig(client, db, fullNameSplit[0], fullNameSplit[1], false)
 		if err != nil {
-			return APIResponse(http.StatusInternalServerError, "error creating client: "+err.Error())
+			return errorResult(errors.New("error getting repo config file: " + err.Error()))
 		}
 
-		fullNameSplit := strings.Split(fullName, "/")
-		file, err := getContent(client, fullNameSplit[0], fullNameSplit[1], ".heupr.yml")
+		if len(files) == 0 {
+			return Result{
+				Status:  http.StatusAccepted,
+				Message: fmt.Sprintf("no config available for %s", fullName),
+			}
+		}
+
+		cfg, file, source, err := mergeConfig(files, bknds)
 		if err != nil {
-			return APIResponse(http.StatusInternalServerError, "error getting repo config file: "+err.Error())
+			return errorResult(newAPIError(http.StatusUnprocessableEntity, codeInvalidConfig, "error parsing repo config file: "+err.Error()))
 		}
-		log.Printf("file content: %s\n", file)
+		log.Printf("file source: %s, content: %s\n", source, file)
 
 		backendPayload := &payload{
-			T: eventType,
-			B: body,
-			C: []byte(file),
+			T:   d.Event,
+			B:   body,
+			C:   file,
+			Src: source,
 		}
 
-		for _, bknd := range bknds {
-			bknd.Configure(client)
-			if err := bknd.Act(backendPayload); err != nil {
-				return APIResponse(http.StatusInternalServerError, "error calling backend act: "+err.Error())
-			}
+		req := backend.Request{
+			DeliveryID: d.ID,
+			Event:      d.Event,
+			Repo:       fullName,
 		}
 
-	default:
-		message := fmt.Sprintf("event type %s not supported", eventType)
-		log.Println(message)
-		return APIResponse(http.StatusInternalServerError, message)
+		bkndClient, rec := dryRun(client, cfg)
+		bkndResults := run(ctx, req, enabled(cfg, bknds, d.Event, d.Body, false), func(ctx context.Context, req backend.Request, bknd backend.BackendV2) error {
+			bknd.Configure(ctx, req, bkndClient)
+			return bknd.Act(ctx, req, backendPayload.forBackend(cfg, req.Backend))
+		})
+
+		result.Repositories = append(result.Repositories, RepoResult{
+			Repository:   fullName,
+			ConfigSource: source,
+			Backends:     bkndResults,
+			DryRun:       finishDryRun(client, cfg, rec, fullName, d.Body),
+		})
 	}
 
-	log.Println("successful event handler invocation")
-	return APIResponse(http.StatusOK, "success")
+	if failed, succeeded := result.failures(); len(failed) > 0 {
+		result.Status = http.StatusInternalServerError
+		result.Code = codeBackendFailure
+		result.Message = fmt.Sprintf("error calling backends: failed [%s], succeeded [%s]", strings.Join(failed, "; "), strings.Join(succeeded, "; "))
+		return result
+	}
+
+	log.Println("successful delivery processing")
+	return result
 }
@@ -2,39 +2,162 @@ package frontend
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/google/go-github/v28/github"
 
 	"github.com/heupr/heupr/backend"
+	"github.com/heupr/heupr/config"
 )
 
 type databaseMock struct {
-	putErr  error
-	getResp installConfig
-	getErr  error
+	putErr       error
+	getResp      installConfig
+	getErr       error
+	configs      map[string]cachedConfig
+	putConfigErr error
+	getConfigErr error
+	deliveries   map[string]bool
+	deliveryErr  error
 }
 
 func (mock *databaseMock) Put(input installConfig) error {
 	return mock.putErr
 }
 
+func (mock *databaseMock) PutInstallation(input installation) error {
+	return mock.putErr
+}
+
+func (mock *databaseMock) PutRepo(input repository) error {
+	return mock.putErr
+}
+
 func (mock *databaseMock) Get(key interface{}) (installConfig, error) {
 	return mock.getResp, mock.getErr
 }
 
+func (mock *databaseMock) PutConfig(input cachedConfig) error {
+	if mock.putConfigErr != nil {
+		return mock.putConfigErr
+	}
+
+	if mock.configs == nil {
+		mock.configs = make(map[string]cachedConfig)
+	}
+	mock.configs[input.Path] = input
+	return nil
+}
+
+func (mock *databaseMock) GetConfig(path string) (cachedConfig, error) {
+	if mock.getConfigErr != nil {
+		return cachedConfig{}, mock.getConfigErr
+	}
+
+	cached, ok := mock.configs[path]
+	if !ok {
+		return cachedConfig{}, errNotCached
+	}
+	return cached, nil
+}
+
+func (mock *databaseMock) PutDelivery(id string, expires time.Time) (bool, error) {
+	if mock.deliveryErr != nil {
+		return false, mock.deliveryErr
+	}
+
+	if mock.deliveries == nil {
+		mock.deliveries = make(map[string]bool)
+	}
+	if mock.deliveries[id] {
+		return false, nil
+	}
+	mock.deliveries[id] = true
+	return true, nil
+}
+
+func (mock *databaseMock) DeleteDelivery(id string) error {
+	delete(mock.deliveries, id)
+	return nil
+}
+
+func resultBody(code, msg string, repos ...RepoResult) string {
+	body, _ := json.Marshal(&Result{Code: code, Message: msg, Repositories: repos})
+	return string(body)
+}
+
+func testRepo(bknds ...BackendResult) RepoResult {
+	return RepoResult{
+		Repository:   "test-owner/test-name",
+		ConfigSource: "test-owner/test-name/.heupr.yml",
+		Backends:     append([]BackendResult{}, bknds...),
+	}
+}
+
+func errorBody(code, msg string) string {
+	return resultBody(code, msg)
+}
+
+func Test_errorResponse(t *testing.T) {
+	tests := []struct {
+		desc     string
+		err      error
+		status   int
+		respBody string
+	}{
+		{
+			desc:     "api error",
+			err:      newAPIError(http.StatusUnauthorized, codeInvalidSignature, "error validating event: mock validate error"),
+			status:   401,
+			respBody: `{"code":"invalid_signature","message":"error validating event: mock validate error"}`,
+		},
+		{
+			desc:     "other error",
+			err:      errors.New("mock error"),
+			status:   500,
+			respBody: `{"code":"internal_error","message":"mock error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		resp, err := errorResponse(test.err)
+		if err != nil {
+			t.Errorf("description: %s, unexpected error: %s", test.desc, err.Error())
+		}
+
+		if resp.StatusCode != test.status {
+			t.Errorf("description: %s, incorrect status code, received: %d, expected: %d", test.desc, resp.StatusCode, test.status)
+		}
+
+		if resp.Body != test.respBody {
+			t.Errorf("description: %s, incorrect body, received: %s, expected: %s", test.desc, resp.Body, test.respBody)
+		}
+
+		if resp.Headers["Content-Type"] != "application/json" {
+			t.Errorf("description: %s, incorrect content type, received: %s", test.desc, resp.Headers["Content-Type"])
+		}
+	}
+}
+
 func TestInstall(t *testing.T) {
+	defer saveStubs()()
+
 	tests := []struct {
 		desc     string
 		code     string
 		postResp *http.Response
 		postErr  error
 		putErr   error
-		err      string
 		status   int
 		respBody string
 	}{
@@ -44,9 +167,8 @@ func TestInstall(t *testing.T) {
 			postResp: nil,
 			postErr:  nil,
 			putErr:   nil,
-			err:      "no code received",
 			status:   400,
-			respBody: "no code received",
+			respBody: errorBody(codeBadRequest, "no code received"),
 		},
 		{
 			desc:     "error requesting temporary manifest code",
@@ -54,9 +176,8 @@ func TestInstall(t *testing.T) {
 			postResp: nil,
 			postErr:  errors.New("mock post error"),
 			putErr:   nil,
-			err:      "error converting code: mock post error",
 			status:   500,
-			respBody: "error converting code: mock post error",
+			respBody: errorBody(codeInternal, "error converting code: mock post error"),
 		},
 		{
 			desc: "error saving config values",
@@ -66,9 +187,8 @@ func TestInstall(t *testing.T) {
 			},
 			postErr:  nil,
 			putErr:   errors.New("mock put error"),
-			err:      "error putting app config: mock put error",
 			status:   500,
-			respBody: "error putting app config: mock put error",
+			respBody: errorBody(codeInternal, "error putting app config: mock put error"),
 		},
 		{
 			desc: "successful invocation",
@@ -78,7 +198,6 @@ func TestInstall(t *testing.T) {
 			},
 			postErr:  nil,
 			putErr:   nil,
-			err:      "",
 			status:   302,
 			respBody: "success",
 		},
@@ -100,8 +219,8 @@ func TestInstall(t *testing.T) {
 		}
 
 		resp, err := Install(req, db)
-		if err != nil && err.Error() != test.err {
-			t.Errorf("description: %s, incorrect error message, received: %s, expected: %s", test.desc, err.Error(), test.err)
+		if err != nil {
+			t.Errorf("description: %s, unexpected error: %s", test.desc, err.Error())
 		}
 
 		if resp.StatusCode != test.status {
@@ -116,8 +235,9 @@ func TestInstall(t *testing.T) {
 
 func Test_payloadMethods(t *testing.T) {
 	p := &payload{
-		B: []byte("content"),
-		T: "type",
+		B:   []byte("content"),
+		T:   "type",
+		Src: "default",
 	}
 
 	bytesOut := p.Bytes()
@@ -129,6 +249,26 @@ func Test_payloadMethods(t *testing.T) {
 	if typeOutput == "" {
 		t.Error("description: no type string returned")
 	}
+
+	if p.ConfigSource() != "default" {
+		t.Errorf("description: incorrect config source returned, received: %s", p.ConfigSource())
+	}
+
+	cfg, err := config.Parse([]byte("backends:\n- name: projectboard\n  settings:\n    urls:\n    - https://coruscant.com\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := struct {
+		URLs []string `yaml:"urls"`
+	}{}
+	if err := p.forBackend(cfg, "projectboard").Settings(&settings); err != nil {
+		t.Errorf("description: error decoding settings, error: %s", err.Error())
+	}
+
+	if len(settings.URLs) != 1 {
+		t.Errorf("description: incorrect settings returned, received: %+v", settings)
+	}
 }
 
 func int64Ptr(input int64) *int64 {
@@ -152,24 +292,67 @@ func stringPtr(input string) *string {
 type testBackend struct {
 	prepareErr error
 	actErr     error
+	delay      time.Duration
+	events     []string
 }
 
-func (tb *testBackend) Configure(*github.Client) {}
+func (tb *testBackend) Manifest() backend.Manifest {
+	if tb.events == nil {
+		return backend.Manifest{Events: backend.DefaultEvents}
+	}
+	return backend.Manifest{Events: tb.events}
+}
 
-func (tb *testBackend) Prepare(backend.Payload) error {
+func (tb *testBackend) Configure(context.Context, backend.Request, *github.Client) {}
+
+func (tb *testBackend) Prepare(ctx context.Context, r backend.Request, p backend.Payload) error {
+	time.Sleep(tb.delay)
 	return tb.prepareErr
 }
 
-func (tb *testBackend) Act(backend.Payload) error {
+func (tb *testBackend) Act(ctx context.Context, r backend.Request, p backend.Payload) error {
+	time.Sleep(tb.delay)
 	return tb.actErr
 }
 
+// testFactories wraps the backends in a registry, listing nil backends as failed
+func testFactories(bknds map[string]backend.BackendV2) *backend.Registry {
+	output := backend.NewRegistry()
+	for name, bknd := range bknds {
+		if bknd == nil {
+			output.Add(name, nil)
+			continue
+		}
+		bknd := bknd
+		output.Add(name, func() backend.BackendV2 {
+			return bknd
+		})
+	}
+	return output
+}
+
+// handle runs the event handler and processes any delivery it queues
+func handle(req events.APIGatewayProxyRequest, db Database, bknds *backend.Registry) (events.APIGatewayProxyResponse, error) {
+	q := NewMemoryQueue()
+	resp, err := Event(req, db, q, bknds)
+
+	msgs, _ := q.Receive()
+	if len(msgs) == 0 {
+		return resp, err
+	}
+
+	return APIResponse(Process(context.Background(), msgs[0].Delivery, db, bknds))
+}
+
 func TestEvent(t *testing.T) {
+	defer saveStubs()()
+
+	backendTimeout = 50 * time.Millisecond
 	tests := []struct {
 		desc           string
 		body           string
 		headers        map[string]string
-		bknds          []backend.Backend
+		bknds          map[string]backend.BackendV2
 		getResp        installConfig
 		getErr         error
 		putErr         error
@@ -177,7 +360,9 @@ func TestEvent(t *testing.T) {
 		clientErr      error
 		getContentResp string
 		getContentErr  error
-		err            string
+		checkRunErr    error
+		putConfigErr   error
+		deliveryErr    error
 		status         int
 		respBody       string
 	}{
@@ -188,7 +373,7 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "test-event",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds:          []backend.Backend{},
+			bknds:          map[string]backend.BackendV2{},
 			getResp:        installConfig{},
 			getErr:         nil,
 			putErr:         nil,
@@ -196,9 +381,26 @@ func TestEvent(t *testing.T) {
 			clientErr:      nil,
 			getContentResp: "",
 			getContentErr:  nil,
-			err:            "event type test-event not supported",
-			status:         500,
-			respBody:       "event type test-event not supported",
+			status:         202,
+			respBody:       resultBody("", "event type test-event not supported"),
+		},
+		{
+			desc: "ping event",
+			body: `{"zen": "Design for failure.", "hook_id": 66}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "ping",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds:          map[string]backend.BackendV2{},
+			getResp:        installConfig{},
+			getErr:         errors.New("mock get error"),
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "",
+			getContentErr:  nil,
+			status:         200,
+			respBody:       resultBody("", "pong"),
 		},
 		{
 			desc: "error getting config",
@@ -207,7 +409,7 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "installation_repositories",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds:          []backend.Backend{},
+			bknds:          map[string]backend.BackendV2{},
 			getResp:        installConfig{},
 			getErr:         errors.New("mock get error"),
 			putErr:         nil,
@@ -215,9 +417,26 @@ func TestEvent(t *testing.T) {
 			clientErr:      nil,
 			getContentResp: "",
 			getContentErr:  nil,
-			err:            "error getting config: mock get error",
 			status:         500,
-			respBody:       "error getting config: mock get error",
+			respBody:       errorBody(codeInternal, "error getting config: mock get error"),
+		},
+		{
+			desc: "unknown installation",
+			body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "issues",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds:          map[string]backend.BackendV2{},
+			getResp:        installConfig{},
+			getErr:         errNotInstalled,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "",
+			getContentErr:  nil,
+			status:         404,
+			respBody:       errorBody(codeUnknownInstallation, "error getting config: installation not found"),
 		},
 		{
 			desc: "error validating received event",
@@ -226,7 +445,7 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "installation_repositories",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds:          []backend.Backend{},
+			bknds:          map[string]backend.BackendV2{},
 			getResp:        installConfig{},
 			getErr:         nil,
 			putErr:         nil,
@@ -234,9 +453,8 @@ func TestEvent(t *testing.T) {
 			clientErr:      nil,
 			getContentResp: "",
 			getContentErr:  nil,
-			err:            "error validating event: mock validate error",
-			status:         500,
-			respBody:       "error validating event: mock validate error",
+			status:         401,
+			respBody:       errorBody(codeInvalidSignature, "error validating event: mock validate error"),
 		},
 		{
 			desc: "error creating client",
@@ -245,7 +463,7 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "installation_repositories",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds:          []backend.Backend{},
+			bknds:          map[string]backend.BackendV2{},
 			getResp:        installConfig{},
 			getErr:         nil,
 			putErr:         nil,
@@ -253,18 +471,17 @@ func TestEvent(t *testing.T) {
 			clientErr:      errors.New("mock client error"),
 			getContentResp: "",
 			getContentErr:  nil,
-			err:            "error creating client: mock client error",
 			status:         500,
-			respBody:       "error creating client: mock client error",
+			respBody:       errorBody(codeInternal, "error creating client: mock client error"),
 		},
 		{
-			desc: "error putting config data",
+			desc: "error putting installation data",
 			body: `{"installation": {"app_id": 1, "id": 2}, "repositories_added": [{"owner": {"login": "test-login"}, "name": "test-name", "full_name": "test-fullname"}]}`,
 			headers: map[string]string{
 				"X-GitHub-Event":  "installation_repositories",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds:          []backend.Backend{},
+			bknds:          map[string]backend.BackendV2{},
 			getResp:        installConfig{},
 			getErr:         nil,
 			putErr:         errors.New("mock put error"),
@@ -272,9 +489,8 @@ func TestEvent(t *testing.T) {
 			clientErr:      nil,
 			getContentResp: "",
 			getContentErr:  nil,
-			err:            "error putting app config: mock put error",
 			status:         500,
-			respBody:       "error putting app config: mock put error",
+			respBody:       errorBody(codeInternal, "error putting installation: mock put error"),
 		},
 		{
 			desc: "error getting repo config content",
@@ -283,7 +499,7 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "installation_repositories",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds:          []backend.Backend{},
+			bknds:          map[string]backend.BackendV2{},
 			getResp:        installConfig{},
 			getErr:         nil,
 			putErr:         nil,
@@ -291,9 +507,8 @@ func TestEvent(t *testing.T) {
 			clientErr:      nil,
 			getContentResp: "",
 			getContentErr:  errors.New("mock content error"),
-			err:            "error getting repo config file: mock content error",
 			status:         500,
-			respBody:       "error getting repo config file: mock content error",
+			respBody:       errorBody(codeInternal, "error getting repo config file: mock content error"),
 		},
 		{
 			desc: "error calling install event backend prepare",
@@ -302,8 +517,8 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "installation_repositories",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds: []backend.Backend{
-				&testBackend{
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
 					prepareErr: errors.New("mock prepare error"),
 					actErr:     nil,
 				},
@@ -313,11 +528,10 @@ func TestEvent(t *testing.T) {
 			putErr:         nil,
 			validateErr:    nil,
 			clientErr:      nil,
-			getContentResp: "",
+			getContentResp: "backends:\n- name: test-backend\n",
 			getContentErr:  nil,
-			err:            "error calling backend prepare: mock prepare error",
 			status:         500,
-			respBody:       "error calling backend prepare: mock prepare error",
+			respBody:       resultBody(codeBackendFailure, "error calling backends: failed [test-backend (test-owner/test-name): mock prepare error], succeeded []", testRepo(BackendResult{Backend: "test-backend", Error: "mock prepare error"})),
 		},
 		{
 			desc: "successful install event invocation",
@@ -326,8 +540,8 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "installation_repositories",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds: []backend.Backend{
-				&testBackend{
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
 					prepareErr: nil,
 					actErr:     nil,
 				},
@@ -337,11 +551,10 @@ func TestEvent(t *testing.T) {
 			putErr:         nil,
 			validateErr:    nil,
 			clientErr:      nil,
-			getContentResp: "",
+			getContentResp: "backends:\n- name: test-backend\n",
 			getContentErr:  nil,
-			err:            "",
 			status:         200,
-			respBody:       "success",
+			respBody:       resultBody("", "success", testRepo(BackendResult{Backend: "test-backend", Succeeded: true})),
 		},
 		{
 			desc: "error getting config from database",
@@ -350,7 +563,7 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "issues",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds:          []backend.Backend{},
+			bknds:          map[string]backend.BackendV2{},
 			getResp:        installConfig{},
 			getErr:         errors.New("mock get error"),
 			putErr:         nil,
@@ -358,9 +571,8 @@ func TestEvent(t *testing.T) {
 			clientErr:      nil,
 			getContentResp: "",
 			getContentErr:  nil,
-			err:            "error getting config: mock get error",
 			status:         500,
-			respBody:       "error getting config: mock get error",
+			respBody:       errorBody(codeInternal, "error getting config: mock get error"),
 		},
 		{
 			desc: "error validating received event",
@@ -369,7 +581,7 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "issues",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds:          []backend.Backend{},
+			bknds:          map[string]backend.BackendV2{},
 			getResp:        installConfig{},
 			getErr:         nil,
 			putErr:         nil,
@@ -377,9 +589,8 @@ func TestEvent(t *testing.T) {
 			clientErr:      nil,
 			getContentResp: "",
 			getContentErr:  nil,
-			err:            "error validating event: mock validate error",
-			status:         500,
-			respBody:       "error validating event: mock validate error",
+			status:         401,
+			respBody:       errorBody(codeInvalidSignature, "error validating event: mock validate error"),
 		},
 		{
 			desc: "error creating client",
@@ -388,7 +599,7 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "issues",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds:          []backend.Backend{},
+			bknds:          map[string]backend.BackendV2{},
 			getResp:        installConfig{},
 			getErr:         nil,
 			putErr:         nil,
@@ -396,9 +607,8 @@ func TestEvent(t *testing.T) {
 			clientErr:      errors.New("mock client error"),
 			getContentResp: "",
 			getContentErr:  nil,
-			err:            "error creating client: mock client error",
 			status:         500,
-			respBody:       "error creating client: mock client error",
+			respBody:       errorBody(codeInternal, "error creating client: mock client error"),
 		},
 		{
 			desc: "error getting repo config content",
@@ -407,7 +617,7 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "issues",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds:          []backend.Backend{},
+			bknds:          map[string]backend.BackendV2{},
 			getResp:        installConfig{},
 			getErr:         nil,
 			putErr:         nil,
@@ -415,9 +625,118 @@ func TestEvent(t *testing.T) {
 			clientErr:      nil,
 			getContentResp: "",
 			getContentErr:  errors.New("mock content error"),
-			err:            "error getting repo config file: mock content error",
 			status:         500,
-			respBody:       "error getting repo config file: mock content error",
+			respBody:       errorBody(codeInternal, "error getting repo config file: mock content error"),
+		},
+		{
+			desc: "no config available for repo",
+			body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "issues",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					actErr: errors.New("mock act error"),
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "",
+			getContentErr:  errNotFound,
+			status:         202,
+			respBody:       resultBody("", "no config available for test-owner/test-name"),
+		},
+		{
+			desc: "error parsing repo config content",
+			body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "issues",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds:          map[string]backend.BackendV2{},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "-------",
+			getContentErr:  nil,
+			status:         422,
+			respBody:       errorBody(codeInvalidConfig, "error parsing repo config file: line 1: cannot unmarshal !!str `-------` into config.Config"),
+		},
+		{
+			desc: "invalid repo config content",
+			body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "issues",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n- name: missing-backend\n",
+			getContentErr:  nil,
+			status:         422,
+			respBody:       errorBody(codeInvalidConfig, `error parsing repo config file: line 3: unknown backend "missing-backend"`),
+		},
+		{
+			desc: "backend not listed in repo config",
+			body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "issues",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					prepareErr: nil,
+					actErr:     errors.New("mock act error"),
+				},
+				"other-backend": &testBackend{
+					prepareErr: nil,
+					actErr:     nil,
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: other-backend\n",
+			getContentErr:  nil,
+			status:         200,
+			respBody:       resultBody("", "success", testRepo(BackendResult{Backend: "other-backend", Succeeded: true})),
+		},
+		{
+			desc: "backend not subscribed to event action",
+			body: `{"action": "closed", "repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "issues",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					prepareErr: nil,
+					actErr:     errors.New("mock act error"),
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n  events:\n  - name: issues\n    actions:\n    - opened\n",
+			getContentErr:  nil,
+			status:         200,
+			respBody:       resultBody("", "success", testRepo()),
 		},
 		{
 			desc: "error calling issue event backend act",
@@ -426,8 +745,8 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "issues",
 				"X-Hub-Signature": "test-issues",
 			},
-			bknds: []backend.Backend{
-				&testBackend{
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
 					prepareErr: nil,
 					actErr:     errors.New("mock act error"),
 				},
@@ -437,11 +756,65 @@ func TestEvent(t *testing.T) {
 			putErr:         nil,
 			validateErr:    nil,
 			clientErr:      nil,
-			getContentResp: "",
+			getContentResp: "backends:\n- name: test-backend\n",
+			getContentErr:  nil,
+			status:         500,
+			respBody:       resultBody(codeBackendFailure, "error calling backends: failed [test-backend (test-owner/test-name): mock act error], succeeded []", testRepo(BackendResult{Backend: "test-backend", Error: "mock act error"})),
+		},
+		{
+			desc: "error calling one of multiple backends",
+			body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "issues",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					prepareErr: nil,
+					actErr:     errors.New("mock act error"),
+				},
+				"other-backend": &testBackend{
+					prepareErr: nil,
+					actErr:     nil,
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n- name: other-backend\n",
+			getContentErr:  nil,
+			status:         500,
+			respBody:       resultBody(codeBackendFailure, "error calling backends: failed [test-backend (test-owner/test-name): mock act error], succeeded [other-backend (test-owner/test-name)]", testRepo(BackendResult{Backend: "other-backend", Succeeded: true}, BackendResult{Backend: "test-backend", Error: "mock act error"})),
+		},
+		{
+			desc: "backend timing out",
+			body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "issues",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					prepareErr: nil,
+					actErr:     nil,
+					delay:      time.Second,
+				},
+				"other-backend": &testBackend{
+					prepareErr: nil,
+					actErr:     nil,
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n- name: other-backend\n",
 			getContentErr:  nil,
-			err:            "error calling backend act: mock act error",
 			status:         500,
-			respBody:       "error calling backend act: mock act error",
+			respBody:       resultBody(codeBackendFailure, "error calling backends: failed [test-backend (test-owner/test-name): timed out after 50ms], succeeded [other-backend (test-owner/test-name)]", testRepo(BackendResult{Backend: "other-backend", Succeeded: true}, BackendResult{Backend: "test-backend", Error: "timed out after 50ms"})),
 		},
 		{
 			desc: "successful issue event invocation",
@@ -450,8 +823,8 @@ func TestEvent(t *testing.T) {
 				"X-GitHub-Event":  "issues",
 				"X-Hub-Signature": "test-signature",
 			},
-			bknds: []backend.Backend{
-				&testBackend{
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
 					prepareErr: nil,
 					actErr:     nil,
 				},
@@ -461,41 +834,1097 @@ func TestEvent(t *testing.T) {
 			putErr:         nil,
 			validateErr:    nil,
 			clientErr:      nil,
-			getContentResp: "",
+			getContentResp: "backends:\n- name: test-backend\n",
 			getContentErr:  nil,
-			err:            "",
 			status:         200,
-			respBody:       "success",
+			respBody:       resultBody("", "success", testRepo(BackendResult{Backend: "test-backend", Succeeded: true})),
 		},
-	}
-
-	for _, test := range tests {
-		validateEvent = func(secret, signature string, body []byte) error {
-			return test.validateErr
-		}
-
-		newClient = func(appID, installationID int64, file string) (*github.Client, error) {
-			return github.NewClient(nil), test.clientErr
-		}
-
-		getContent = func(c *github.Client, owner, repo, path string) (string, error) {
-			return test.getContentResp, test.getContentErr
-		}
-
-		req := events.APIGatewayProxyRequest{
-			Body:    test.body,
-			Headers: test.headers,
-		}
-
-		db := &databaseMock{
-			getResp: test.getResp,
-			getErr:  test.getErr,
-			putErr:  test.putErr,
-		}
+		{
+			desc: "error recording delivery",
+			body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":    "issues",
+				"X-Hub-Signature":   "test-signature",
+				"X-GitHub-Delivery": "test-delivery",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{},
+			},
+			getResp:        installConfig{},
+			getContentResp: "backends:\n- name: test-backend\n",
+			deliveryErr:    errors.New("mock delivery error"),
+			status:         500,
+			respBody:       errorBody(codeInternal, "error recording delivery: mock delivery error"),
+		},
+		{
+			desc: "push event without repo config changes",
+			body: `{"after": "test-sha", "commits": [{"modified": ["README.md"]}], "repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					actErr: errors.New("mock act error"),
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n",
+			getContentErr:  nil,
+			checkRunErr:    errors.New("error creating check run: mock check run error"),
+			status:         200,
+			respBody:       resultBody("", "success", testRepo()),
+		},
+		{
+			desc: "push event forwarded to backend declaring push",
+			body: `{"after": "test-sha", "commits": [{"modified": ["README.md"]}], "repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					actErr: errors.New("mock act error"),
+					events: []string{"push"},
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n",
+			getContentErr:  nil,
+			checkRunErr:    nil,
+			status:         500,
+			respBody:       resultBody(codeBackendFailure, "error calling backends: failed [test-backend (test-owner/test-name): mock act error], succeeded []", testRepo(BackendResult{Backend: "test-backend", Error: "mock act error"})),
+		},
+		{
+			desc: "event type declared in backend manifest",
+			body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "gollum",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					events: []string{"gollum"},
+				},
+				"other-backend": &testBackend{
+					actErr: errors.New("mock act error"),
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n- name: other-backend\n",
+			getContentErr:  nil,
+			checkRunErr:    nil,
+			status:         200,
+			respBody:       resultBody("", "success", testRepo(BackendResult{Backend: "test-backend", Succeeded: true})),
+		},
+		{
+			desc: "config listing backend that failed to load",
+			body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "issues",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend":   &testBackend{},
+				"failed-backend": nil,
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n- name: failed-backend\n  settings:\n    enabled: true\n",
+			getContentErr:  nil,
+			checkRunErr:    nil,
+			status:         200,
+			respBody:       resultBody("", "success", testRepo(BackendResult{Backend: "test-backend", Succeeded: true})),
+		},
+		{
+			desc: "config listing event type declared only in backend manifest",
+			body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "deploy_review",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					events: []string{"deploy_review"},
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n  events:\n  - name: deploy_review\n",
+			getContentErr:  nil,
+			checkRunErr:    nil,
+			status:         200,
+			respBody:       resultBody("", "success", testRepo(BackendResult{Backend: "test-backend", Succeeded: true})),
+		},
+		{
+			desc: "error creating repo config check run logged and event processed",
+			body: `{"after": "test-sha", "commits": [{"modified": [".heupr.yml"]}], "repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					events: []string{"push"},
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n",
+			getContentErr:  nil,
+			checkRunErr:    errors.New("error creating check run: mock check run error"),
+			status:         200,
+			respBody:       resultBody("", "success", testRepo(BackendResult{Backend: "test-backend", Succeeded: true})),
+		},
+		{
+			desc: "error caching repo config file",
+			body: `{"ref": "refs/heads/master", "after": "test-sha", "commits": [{"modified": [".heupr.yml"]}], "repository": {"full_name": "test-owner/test-name", "default_branch": "master"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds:          map[string]backend.BackendV2{},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends: []\n",
+			getContentErr:  nil,
+			checkRunErr:    nil,
+			putConfigErr:   errors.New("mock put config error"),
+			status:         500,
+			respBody:       errorBody(codeInternal, "error caching repo config file: mock put config error"),
+		},
+		{
+			desc: "repo config file removed on default branch",
+			body: `{"ref": "refs/heads/master", "after": "test-sha", "commits": [{"removed": [".heupr.yml"]}], "repository": {"full_name": "test-owner/test-name", "default_branch": "master"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds:          map[string]backend.BackendV2{},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "",
+			getContentErr:  errNotFound,
+			checkRunErr:    errors.New("error creating check run: mock check run error"),
+			status:         202,
+			respBody:       resultBody("", "no config available for test-owner/test-name"),
+		},
+		{
+			desc: "error parsing issue comment event",
+			body: `{"action": "created", "repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "issue_comment",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds:          map[string]backend.BackendV2{},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "",
+			getContentErr:  nil,
+			status:         400,
+			respBody:       errorBody(codeBadRequest, "error parsing event: issue_comment payload missing repository, issue, or comment"),
+		},
+		{
+			desc: "pull request review event forwarded to backend declaring it",
+			body: `{"action": "submitted", "repository": {"full_name": "test-owner/test-name"}, "pull_request": {"number": 66}, "review": {"state": "approved"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "pull_request_review",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					actErr: errors.New("mock act error"),
+					events: []string{"pull_request_review"},
+				},
+				"other-backend": &testBackend{
+					actErr: errors.New("mock act error"),
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n  events:\n  - name: pull_request_review\n    actions:\n    - submitted\n- name: other-backend\n",
+			getContentErr:  nil,
+			status:         500,
+			respBody:       resultBody(codeBackendFailure, "error calling backends: failed [test-backend (test-owner/test-name): mock act error], succeeded []", testRepo(BackendResult{Backend: "test-backend", Error: "mock act error"})),
+		},
+		{
+			desc: "successful pull request review comment event invocation",
+			body: `{"action": "created", "repository": {"full_name": "test-owner/test-name"}, "pull_request": {"number": 66}, "comment": {"body": "not to worry"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "pull_request_review_comment",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					events: []string{"pull_request_review_comment"},
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n",
+			getContentErr:  nil,
+			status:         200,
+			respBody:       resultBody("", "success", testRepo(BackendResult{Backend: "test-backend", Succeeded: true})),
+		},
+		{
+			desc: "successful push event invocation",
+			body: `{"after": "test-sha", "commits": [{"added": [".heupr.yml"]}], "repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": "test-signature",
+			},
+			bknds: map[string]backend.BackendV2{
+				"test-backend": &testBackend{
+					events: []string{"push"},
+				},
+			},
+			getResp:        installConfig{},
+			getErr:         nil,
+			putErr:         nil,
+			validateErr:    nil,
+			clientErr:      nil,
+			getContentResp: "backends:\n- name: test-backend\n",
+			getContentErr:  nil,
+			checkRunErr:    nil,
+			status:         200,
+			respBody:       resultBody("", "success", testRepo(BackendResult{Backend: "test-backend", Succeeded: true})),
+		},
+	}
+
+	for _, test := range tests {
+		validateEvent = func(secret, signature string, body []byte) error {
+			return test.validateErr
+		}
+
+		newClient = func(appID, installationID int64, file string) (*github.Client, error) {
+			return github.NewClient(nil), test.clientErr
+		}
+
+		getContent = func(c *github.Client, owner, repo, path, ref string) (string, string, error) {
+			if repo == ".github" {
+				return "", "", errNotFound
+			}
+			return test.getContentResp, "test-sha", test.getContentErr
+		}
+
+		createCheckRun = func(c *github.Client, owner, repo string, opts github.CreateCheckRunOptions) error {
+			return test.checkRunErr
+		}
+
+		req := events.APIGatewayProxyRequest{
+			Body:    test.body,
+			Headers: test.headers,
+		}
+
+		db := &databaseMock{
+			getResp:      test.getResp,
+			getErr:       test.getErr,
+			putErr:       test.putErr,
+			putConfigErr: test.putConfigErr,
+			deliveryErr:  test.deliveryErr,
+		}
+
+		resp, err := handle(req, db, testFactories(test.bknds))
+		if err != nil {
+			t.Errorf("description: %s, unexpected error: %s", test.desc, err.Error())
+		}
+
+		if resp.StatusCode != test.status {
+			t.Errorf("description: %s, incorrect status code, received: %d, expected: %d", test.desc, resp.StatusCode, test.status)
+		}
+
+		if resp.Body != test.respBody {
+			t.Errorf("description: %s, incorrect body, received: %s, expected: %s", test.desc, resp.Body, test.respBody)
+		}
+	}
+}
+
+// commentBackend reads the comment and review events with the typed payload helpers
+type commentBackend struct {
+	testBackend
+	received []string
+}
+
+func (cb *commentBackend) Manifest() backend.Manifest {
+	return backend.Manifest{Events: []string{"issue_comment", "pull_request_review", "pull_request_review_comment"}}
+}
+
+func (cb *commentBackend) Act(ctx context.Context, r backend.Request, p backend.Payload) error {
+	switch p.Type() {
+	case "issue_comment":
+		event, err := backend.IssueComment(p)
+		if err != nil {
+			return err
+		}
+		cb.received = append(cb.received, fmt.Sprintf("issue %d: %s", event.Issue.GetNumber(), event.Comment.GetBody()))
+	case "pull_request_review":
+		event, err := backend.PullRequestReview(p)
+		if err != nil {
+			return err
+		}
+		cb.received = append(cb.received, fmt.Sprintf("pull request %d: %s", event.PullRequest.GetNumber(), event.Review.GetState()))
+	case "pull_request_review_comment":
+		event, err := backend.PullRequestReviewComment(p)
+		if err != nil {
+			return err
+		}
+		cb.received = append(cb.received, fmt.Sprintf("pull request %d: %s", event.PullRequest.GetNumber(), event.Comment.GetBody()))
+	}
+	return nil
+}
+
+func TestEventCommentBackend(t *testing.T) {
+	defer saveStubs()()
+
+	repo := &github.Repository{FullName: github.String("test-owner/test-name")}
+
+	tests := []struct {
+		desc      string
+		eventType string
+		event     interface{}
+		received  []string
+	}{
+		{
+			desc:      "issue comment",
+			eventType: "issue_comment",
+			event: &github.IssueCommentEvent{
+				Action:  github.String("created"),
+				Repo:    repo,
+				Issue:   &github.Issue{Number: github.Int(66)},
+				Comment: &github.IssueComment{Body: github.String("execute order 66")},
+			},
+			received: []string{"issue 66: execute order 66"},
+		},
+		{
+			desc:      "pull request review",
+			eventType: "pull_request_review",
+			event: &github.PullRequestReviewEvent{
+				Action:      github.String("submitted"),
+				Repo:        repo,
+				PullRequest: &github.PullRequest{Number: github.Int(66)},
+				Review:      &github.PullRequestReview{State: github.String("approved")},
+			},
+			received: []string{"pull request 66: approved"},
+		},
+		{
+			desc:      "pull request review comment",
+			eventType: "pull_request_review_comment",
+			event: &github.PullRequestReviewCommentEvent{
+				Action:      github.String("created"),
+				Repo:        repo,
+				PullRequest: &github.PullRequest{Number: github.Int(66)},
+				Comment:     &github.PullRequestComment{Body: github.String("it's a trap")},
+			},
+			received: []string{"pull request 66: it's a trap"},
+		},
+	}
+
+	validateEvent = func(secret, signature string, body []byte) error {
+		return nil
+	}
+
+	newClient = func(appID, installationID int64, file string) (*github.Client, error) {
+		return github.NewClient(nil), nil
+	}
+
+	getContent = func(c *github.Client, owner, repo, path, ref string) (string, string, error) {
+		if repo == ".github" {
+			return "", "", errNotFound
+		}
+		return "backends:\n- name: comment-backend\n", "test-sha", nil
+	}
+
+	for _, test := range tests {
+		body, err := json.Marshal(test.event)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cb := &commentBackend{}
+		resp, err := handle(events.APIGatewayProxyRequest{
+			Body: string(body),
+			Headers: map[string]string{
+				"X-GitHub-Event":  test.eventType,
+				"X-Hub-Signature": "test-signature",
+			},
+		}, &databaseMock{}, testFactories(map[string]backend.BackendV2{"comment-backend": cb}))
+		if err != nil {
+			t.Errorf("description: %s, unexpected error: %s", test.desc, err.Error())
+		}
+
+		expected := resultBody("", "success", testRepo(BackendResult{Backend: "comment-backend", Succeeded: true}))
+		if resp.StatusCode != 200 || resp.Body != expected {
+			t.Errorf("description: %s, response received: %d %s, expected: 200 %s", test.desc, resp.StatusCode, resp.Body, expected)
+		}
+
+		if !reflect.DeepEqual(cb.received, test.received) {
+			t.Errorf("description: %s, received: %v, expected: %v", test.desc, cb.received, test.received)
+		}
+	}
+}
+
+func Test_cachedContent(t *testing.T) {
+	defer saveStubs()()
+
+	expires := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		desc         string
+		configs      map[string]cachedConfig
+		getConfigErr error
+		refresh      bool
+		contentResp  string
+		contentErr   error
+		file         string
+		err          error
+		cached       cachedConfig
+		expires      time.Time
+	}{
+		{
+			desc: "cached config file",
+			configs: map[string]cachedConfig{
+				"test-owner/test-name/.heupr.yml": {Path: "test-owner/test-name/.heupr.yml", Content: "cached", Expires: expires},
+			},
+			contentResp: "fetched",
+			file:        "cached",
+			cached:      cachedConfig{Path: "test-owner/test-name/.heupr.yml", Content: "cached"},
+			expires:     expires,
+		},
+		{
+			desc: "cached missing config file",
+			configs: map[string]cachedConfig{
+				"test-owner/test-name/.heupr.yml": {Path: "test-owner/test-name/.heupr.yml", Missing: true, Expires: expires},
+			},
+			contentResp: "fetched",
+			err:         errNotFound,
+			cached:      cachedConfig{Path: "test-owner/test-name/.heupr.yml", Missing: true},
+			expires:     expires,
+		},
+		{
+			desc: "expired cached config file",
+			configs: map[string]cachedConfig{
+				"test-owner/test-name/.heupr.yml": {Path: "test-owner/test-name/.heupr.yml", Content: "cached", Expires: time.Now().Add(-time.Minute)},
+			},
+			contentResp: "fetched",
+			file:        "fetched",
+			cached:      cachedConfig{Path: "test-owner/test-name/.heupr.yml", Content: "fetched"},
+		},
+		{
+			desc: "expired cached missing config file",
+			configs: map[string]cachedConfig{
+				"test-owner/test-name/.heupr.yml": {Path: "test-owner/test-name/.heupr.yml", Missing: true},
+			},
+			contentResp: "fetched",
+			file:        "fetched",
+			cached:      cachedConfig{Path: "test-owner/test-name/.heupr.yml", Content: "fetched"},
+		},
+		{
+			desc: "refreshed config file",
+			configs: map[string]cachedConfig{
+				"test-owner/test-name/.heupr.yml": {Path: "test-owner/test-name/.heupr.yml", Content: "cached"},
+			},
+			refresh:     true,
+			contentResp: "fetched",
+			file:        "fetched",
+			cached:      cachedConfig{Path: "test-owner/test-name/.heupr.yml", Content: "fetched"},
+		},
+		{
+			desc:        "uncached config file",
+			contentResp: "fetched",
+			file:        "fetched",
+			cached:      cachedConfig{Path: "test-owner/test-name/.heupr.yml", Content: "fetched"},
+		},
+		{
+			desc:         "error reading cache",
+			getConfigErr: errors.New("mock get config error"),
+			contentResp:  "fetched",
+			file:         "fetched",
+		},
+		{
+			desc:       "uncached missing config file",
+			contentErr: errNotFound,
+			err:        errNotFound,
+			cached:     cachedConfig{Path: "test-owner/test-name/.heupr.yml", Missing: true},
+		},
+	}
+
+	for _, test := range tests {
+		getContent = func(c *github.Client, owner, repo, path, ref string) (string, string, error) {
+			if test.contentErr != nil {
+				return "", "", test.contentErr
+			}
+			return test.contentResp, "fetched-sha", nil
+		}
+
+		db := &databaseMock{
+			configs:      test.configs,
+			getConfigErr: test.getConfigErr,
+		}
+
+		file, err := cachedContent(nil, db, "test-owner", "test-name", test.refresh)
+		if err != test.err {
+			t.Errorf("description: %s, error received: %v, expected: %v", test.desc, err, test.err)
+		}
+
+		if file != test.file {
+			t.Errorf("description: %s, file received: %s, expected: %s", test.desc, file, test.file)
+		}
+
+		if test.getConfigErr != nil {
+			continue
+		}
+
+		cached := db.configs["test-owner/test-name/.heupr.yml"]
+		if test.expires.IsZero() && !cached.Expires.After(time.Now()) {
+			t.Errorf("description: %s, fetched config expires: %s, expected after now", test.desc, cached.Expires)
+		} else if !test.expires.IsZero() && !cached.Expires.Equal(test.expires) {
+			t.Errorf("description: %s, cached config expires: %s, expected: %s", test.desc, cached.Expires, test.expires)
+		}
+
+		cached.Expires = time.Time{}
+		if cached != test.cached {
+			t.Errorf("description: %s, cached config received: %+v, expected: %+v", test.desc, cached, test.cached)
+		}
+	}
+}
+
+func Test_defaultBranch(t *testing.T) {
+	tests := []struct {
+		desc     string
+		body     string
+		expected bool
+	}{
+		{
+			desc:     "push to default branch",
+			body:     `{"ref": "refs/heads/main", "repository": {"default_branch": "main"}}`,
+			expected: true,
+		},
+		{
+			desc:     "push to other branch",
+			body:     `{"ref": "refs/heads/feature", "repository": {"default_branch": "main"}}`,
+			expected: false,
+		},
+		{
+			desc:     "push to tag",
+			body:     `{"ref": "refs/tags/main", "repository": {"default_branch": "main"}}`,
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		if received := defaultBranch(test.body); received != test.expected {
+			t.Errorf("description: %s, received: %t, expected: %t", test.desc, received, test.expected)
+		}
+	}
+}
+
+func Test_resolveConfig(t *testing.T) {
+	defer saveStubs()()
+
+	tests := []struct {
+		desc          string
+		repo          string
+		files         map[string]string
+		contentErr    error
+		defaultConfig string
+		expected      []configFile
+		err           string
+	}{
+		{
+			desc: "organization and repo config files",
+			repo: "test-name",
+			files: map[string]string{
+				"test-owner/test-name": "repo",
+				"test-owner/.github":   "org",
+			},
+			expected: []configFile{
+				{Content: "org", Source: "test-owner/.github/.heupr.yml"},
+				{Content: "repo", Source: "test-owner/test-name/.heupr.yml"},
+			},
+		},
+		{
+			desc: "organization config file",
+			repo: "test-name",
+			files: map[string]string{
+				"test-owner/.github": "org",
+			},
+			defaultConfig: "default",
+			expected: []configFile{
+				{Content: "org", Source: "test-owner/.github/.heupr.yml"},
+			},
+		},
+		{
+			desc: "organization repo config file",
+			repo: ".github",
+			files: map[string]string{
+				"test-owner/.github": "org",
+			},
+			expected: []configFile{
+				{Content: "org", Source: "test-owner/.github/.heupr.yml"},
+			},
+		},
+		{
+			desc:          "default config",
+			repo:          "test-name",
+			files:         map[string]string{},
+			defaultConfig: "default",
+			expected: []configFile{
+				{Content: "default", Source: "default"},
+			},
+		},
+		{
+			desc:          "built-in default config",
+			repo:          "test-name",
+			files:         map[string]string{},
+			defaultConfig: builtinConfig,
+			expected: []configFile{
+				{Content: builtinConfig, Source: "default"},
+			},
+		},
+		{
+			desc:     "no config available",
+			repo:     "test-name",
+			files:    map[string]string{},
+			expected: []configFile{},
+		},
+		{
+			desc:       "error getting content",
+			repo:       "test-name",
+			files:      map[string]string{},
+			contentErr: errors.New("mock content error"),
+			expected:   nil,
+			err:        "mock content error",
+		},
+	}
+
+	for _, test := range tests {
+		getContent = func(c *github.Client, owner, repo, path, ref string) (string, string, error) {
+			if test.contentErr != nil {
+				return "", "", test.contentErr
+			}
 
-		resp, err := Event(req, db, test.bknds)
+			file, ok := test.files[owner+"/"+repo]
+			if !ok {
+				return "", "", errNotFound
+			}
+			return file, "test-sha", nil
+		}
+		defaultConfig = test.defaultConfig
+
+		files, err := resolveConfig(nil, &databaseMock{}, "test-owner", test.repo, false)
 		if err != nil && err.Error() != test.err {
-			t.Errorf("description: %s, incorrect error message, received: %s, expected: %s", test.desc, err.Error(), test.err)
+			t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
+		}
+
+		if !reflect.DeepEqual(files, test.expected) {
+			t.Errorf("description: %s, files received: %+v, expected: %+v", test.desc, files, test.expected)
+		}
+	}
+}
+
+func Test_builtinConfig(t *testing.T) {
+	cfg, err := loadConfig(builtinConfig, backend.NewRegistry())
+	if err != nil {
+		t.Fatalf("description: built-in config invalid, error: %s", err.Error())
+	}
+
+	if len(cfg.Backends) != 0 {
+		t.Errorf("description: built-in config backends received: %+v, expected none", cfg.Backends)
+	}
+}
+
+func Test_mergeConfig(t *testing.T) {
+	bknds := testFactories(map[string]backend.BackendV2{
+		"test-backend":  &testBackend{},
+		"other-backend": &testBackend{},
+	})
+
+	tests := []struct {
+		desc    string
+		files   []configFile
+		content string
+		source  string
+		err     string
+	}{
+		{
+			desc: "single config file",
+			files: []configFile{
+				{Content: "backends:\n  - name: test-backend\n", Source: "test-owner/test-name/.heupr.yml"},
+			},
+			content: "backends:\n  - name: test-backend\n",
+			source:  "test-owner/test-name/.heupr.yml",
+		},
+		{
+			desc: "invalid organization config file",
+			files: []configFile{
+				{Content: "backends:\n- name: missing-backend\n", Source: "test-owner/.github/.heupr.yml"},
+				{Content: "backends:\n- name: test-backend\n", Source: "test-owner/test-name/.heupr.yml"},
+			},
+			err: `test-owner/.github/.heupr.yml: line 2: unknown backend "missing-backend"`,
+		},
+		{
+			desc: "invalid repo config file",
+			files: []configFile{
+				{Content: "backends:\n- name: test-backend\n", Source: "test-owner/.github/.heupr.yml"},
+				{Content: "backends:\n- name: test-backend\n  merge: replace\n", Source: "test-owner/test-name/.heupr.yml"},
+			},
+			err: `line 2: unknown merge strategy "replace" for backend "test-backend"`,
+		},
+		{
+			desc: "merged config files",
+			files: []configFile{
+				{Content: "backends:\n- name: test-backend\n- name: other-backend\n", Source: "test-owner/.github/.heupr.yml"},
+				{Content: "backends:\n- name: test-backend\n  merge: remove\n", Source: "test-owner/test-name/.heupr.yml"},
+			},
+			content: "backends:\n    - name: other-backend\n",
+			source:  "test-owner/.github/.heupr.yml + test-owner/test-name/.heupr.yml",
+		},
+	}
+
+	for _, test := range tests {
+		_, content, source, err := mergeConfig(test.files, bknds)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
+			}
+			continue
+		}
+
+		if string(content) != test.content || source != test.source {
+			t.Errorf("description: %s, received: %q (%s), expected: %q (%s)", test.desc, content, source, test.content, test.source)
+		}
+	}
+}
+
+func Test_supported(t *testing.T) {
+	bknds := testFactories(map[string]backend.BackendV2{
+		"test-backend": &testBackend{
+			events: []string{"gollum"},
+		},
+	})
+
+	tests := []struct {
+		desc      string
+		eventType string
+		expected  bool
+	}{
+		{
+			desc:      "push event",
+			eventType: "push",
+			expected:  true,
+		},
+		{
+			desc:      "default backend event",
+			eventType: "issues",
+			expected:  true,
+		},
+		{
+			desc:      "event with typed backend helper",
+			eventType: "issue_comment",
+			expected:  true,
+		},
+		{
+			desc:      "event declared in backend manifest",
+			eventType: "gollum",
+			expected:  true,
+		},
+		{
+			desc:      "undeclared event",
+			eventType: "watch",
+			expected:  false,
+		},
+	}
+
+	for _, test := range tests {
+		if received := supported(test.eventType, bknds); received != test.expected {
+			t.Errorf("description: %s, received: %t, expected: %t", test.desc, received, test.expected)
+		}
+	}
+}
+
+func Test_configChanged(t *testing.T) {
+	tests := []struct {
+		desc     string
+		body     string
+		expected bool
+	}{
+		{
+			desc:     "no commits",
+			body:     `{"commits": []}`,
+			expected: false,
+		},
+		{
+			desc:     "other files changed",
+			body:     `{"commits": [{"added": ["main.go"], "modified": ["README.md"]}]}`,
+			expected: false,
+		},
+		{
+			desc:     "repo config file removed",
+			body:     `{"commits": [{"removed": [".heupr.yml"]}]}`,
+			expected: true,
+		},
+		{
+			desc:     "branch deleted",
+			body:     `{"deleted": true, "commits": [{"modified": [".heupr.yml"]}]}`,
+			expected: false,
+		},
+		{
+			desc:     "repo config file modified in later commit",
+			body:     `{"commits": [{"added": ["main.go"]}, {"modified": [".heupr.yml"]}]}`,
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		if received := configChanged(test.body); received != test.expected {
+			t.Errorf("description: %s, received: %t, expected: %t", test.desc, received, test.expected)
+		}
+	}
+}
+
+func Test_configCheck(t *testing.T) {
+	bknds := testFactories(map[string]backend.BackendV2{
+		"test-backend": &testBackend{},
+	})
+
+	tests := []struct {
+		desc        string
+		file        string
+		conclusion  string
+		annotations []string
+	}{
+		{
+			desc:        "valid repo config",
+			file:        "backends:\n- name: test-backend\n",
+			conclusion:  "success",
+			annotations: []string{},
+		},
+		{
+			desc:       "invalid repo config",
+			file:       "backends:\n- name: test-backend\n  events:\n  - name: issue\n- name: missing-backend\n",
+			conclusion: "failure",
+			annotations: []string{
+				`4: unknown event "issue" for backend "test-backend"`,
+				`5: unknown backend "missing-backend"`,
+			},
+		},
+		{
+			desc:       "unparsable repo config",
+			file:       "-------",
+			conclusion: "failure",
+			annotations: []string{
+				"1: cannot unmarshal !!str `-------` into config.Config",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		opts := configCheck(test.file, "test-sha", bknds)
+
+		if opts.HeadSHA != "test-sha" || opts.GetStatus() != "completed" {
+			t.Errorf("description: %s, incorrect check run, received: %+v", test.desc, opts)
+		}
+
+		if opts.GetConclusion() != test.conclusion {
+			t.Errorf("description: %s, incorrect conclusion, received: %s, expected: %s", test.desc, opts.GetConclusion(), test.conclusion)
+		}
+
+		annotations := []string{}
+		for _, annotation := range opts.Output.Annotations {
+			annotations = append(annotations, fmt.Sprintf("%d: %s", annotation.GetStartLine(), annotation.GetMessage()))
+		}
+
+		if !reflect.DeepEqual(annotations, test.annotations) {
+			t.Errorf("description: %s, incorrect annotations, received: %v, expected: %v", test.desc, annotations, test.annotations)
+		}
+	}
+}
+
+func Test_subscribed(t *testing.T) {
+	tests := []struct {
+		desc      string
+		bknd      config.Backend
+		eventType string
+		body      string
+		expected  bool
+	}{
+		{
+			desc:      "no events listed",
+			bknd:      config.Backend{Name: "projectboard"},
+			eventType: "project_card",
+			body:      `{"action":"moved"}`,
+			expected:  true,
+		},
+		{
+			desc: "event not listed",
+			bknd: config.Backend{
+				Name:   "assignissue",
+				Events: []config.Event{{Name: "issues"}},
+			},
+			eventType: "pull_request",
+			body:      `{"action":"opened"}`,
+			expected:  false,
+		},
+		{
+			desc: "event listed with no actions",
+			bknd: config.Backend{
+				Name:   "assignissue",
+				Events: []config.Event{{Name: "issues"}},
+			},
+			eventType: "issues",
+			body:      `{"action":"edited"}`,
+			expected:  true,
+		},
+		{
+			desc: "action not listed",
+			bknd: config.Backend{
+				Name:   "assignissue",
+				Events: []config.Event{{Name: "issues", Actions: []string{"opened"}}},
+			},
+			eventType: "issues",
+			body:      `{"action":"closed"}`,
+			expected:  false,
+		},
+		{
+			desc: "merged pull request action",
+			bknd: config.Backend{
+				Name:   "estimatepr",
+				Events: []config.Event{{Name: "pull_request", Actions: []string{"merged"}}},
+			},
+			eventType: "pull_request",
+			body:      `{"action":"closed","pull_request":{"merged":true}}`,
+			expected:  true,
+		},
+		{
+			desc: "unmerged pull request action",
+			bknd: config.Backend{
+				Name:   "estimatepr",
+				Events: []config.Event{{Name: "pull_request", Actions: []string{"merged"}}},
+			},
+			eventType: "pull_request",
+			body:      `{"action":"closed","pull_request":{"merged":false}}`,
+			expected:  false,
+		},
+	}
+
+	for _, test := range tests {
+		if received := subscribed(test.bknd, test.eventType, test.body); received != test.expected {
+			t.Errorf("description: %s, subscription received: %t, expected: %t", test.desc, received, test.expected)
+		}
+	}
+}
+
+func TestEventRedelivery(t *testing.T) {
+	defer saveStubs()()
+
+	validateEvent = func(secret, signature string, body []byte) error {
+		return nil
+	}
+
+	newClient = func(appID, installationID int64, file string) (*github.Client, error) {
+		return github.NewClient(nil), nil
+	}
+
+	tests := []struct {
+		desc     string
+		content  string
+		actErr   error
+		status   int
+		respBody string
+	}{
+		{
+			desc:     "successful delivery not queued again",
+			content:  "backends:\n- name: test-backend\n",
+			actErr:   nil,
+			status:   200,
+			respBody: resultBody("", "delivery test-delivery already received"),
+		},
+		{
+			desc:     "failed backend delivery not queued again",
+			content:  "backends:\n- name: test-backend\n",
+			actErr:   errors.New("mock act error"),
+			status:   200,
+			respBody: resultBody("", "delivery test-delivery already received"),
+		},
+		{
+			desc:     "invalid config delivery queued again",
+			content:  "backends:\n- name: missing-backend\n",
+			actErr:   nil,
+			status:   202,
+			respBody: resultBody("", "event queued"),
+		},
+	}
+
+	for _, test := range tests {
+		getContent = func(c *github.Client, owner, repo, path, ref string) (string, string, error) {
+			if repo == ".github" {
+				return "", "", errNotFound
+			}
+			return test.content, "test-sha", nil
+		}
+
+		req := events.APIGatewayProxyRequest{
+			Body: `{"repository": {"full_name": "test-owner/test-name"}}`,
+			Headers: map[string]string{
+				"X-GitHub-Event":    "issues",
+				"X-Hub-Signature":   "test-signature",
+				"X-GitHub-Delivery": "test-delivery",
+			},
+		}
+
+		db := &databaseMock{}
+		q := NewMemoryQueue()
+		bknds := testFactories(map[string]backend.BackendV2{
+			"test-backend": &testBackend{actErr: test.actErr},
+		})
+
+		if _, err := Event(req, db, q, bknds); err != nil {
+			t.Errorf("description: %s, unexpected error: %s", test.desc, err.Error())
+		}
+
+		if err := Poll(q, db, bknds); err != nil {
+			t.Errorf("description: %s, unexpected poll error: %s", test.desc, err.Error())
+		}
+
+		resp, err := Event(req, db, q, bknds)
+		if err != nil {
+			t.Errorf("description: %s, unexpected error: %s", test.desc, err.Error())
 		}
 
 		if resp.StatusCode != test.status {
@@ -507,3 +1936,38 @@ func TestEvent(t *testing.T) {
 		}
 	}
 }
+
+func Test_run(t *testing.T) {
+	instances := []*testBackend{}
+	mu := sync.Mutex{}
+
+	bknds := map[string]backend.Factory{
+		"test-backend": func() backend.BackendV2 {
+			mu.Lock()
+			defer mu.Unlock()
+
+			bknd := &testBackend{}
+			instances = append(instances, bknd)
+			return bknd
+		},
+	}
+
+	results := [][]BackendResult{}
+	for _, repo := range []string{"jedi/temple", "sith/temple"} {
+		results = append(results, run(context.Background(), backend.Request{Repo: repo}, bknds, func(ctx context.Context, req backend.Request, bknd backend.BackendV2) error {
+			return bknd.Act(ctx, req, nil)
+		}))
+	}
+
+	if len(instances) != 2 || instances[0] == instances[1] {
+		t.Errorf("description: backend instances shared between repos, received: %d", len(instances))
+	}
+
+	expected := [][]BackendResult{
+		{{Backend: "test-backend", Succeeded: true}},
+		{{Backend: "test-backend", Succeeded: true}},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("description: incorrect results, received: %+v, expected: %+v", results, expected)
+	}
+}
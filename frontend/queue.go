@@ -0,0 +1,322 @@
+package frontend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// Delivery is a validated webhook event awaiting processing by the worker
+type Delivery struct {
+	ID       string    `json:"id"`
+	Event    string    `json:"event"`
+	Body     string    `json:"body"`
+	Received time.Time `json:"received"`
+}
+
+// Message is a delivery received from a queue along with its receipt
+type Message struct {
+	Delivery Delivery
+	receipt  string
+}
+
+// Queue passes validated deliveries from the event handler to the worker
+type Queue interface {
+	Send(d Delivery) error
+	Receive() ([]Message, error)
+	Delete(m Message) error
+}
+
+// NewQueue creates the Queue described by the spec: "memory", "file:<directory>", or "sqs:<queue url>"
+func NewQueue(spec string) (Queue, error) {
+	switch {
+	case spec == "memory":
+		return NewMemoryQueue(), nil
+	case strings.HasPrefix(spec, "file:"):
+		return NewFileQueue(strings.TrimPrefix(spec, "file:"))
+	case strings.HasPrefix(spec, "sqs:"):
+		return NewSQSQueue(strings.TrimPrefix(spec, "sqs:")), nil
+	case spec == "":
+		return nil, errors.New("no queue configured")
+	}
+
+	return nil, fmt.Errorf("unsupported queue: %s", spec)
+}
+
+// visibilityTimeout is how long received memory and file queue messages stay hidden
+var visibilityTimeout = durationEnv("HEUPR_QUEUE_VISIBILITY_TIMEOUT", 5*time.Minute)
+
+// NewMemoryQueue creates a Queue held in process memory
+func NewMemoryQueue() Queue {
+	return &memoryQueue{
+		timeout:  visibilityTimeout,
+		inflight: make(map[string]inflightDelivery),
+	}
+}
+
+type inflightDelivery struct {
+	delivery Delivery
+	count    int
+	received time.Time
+}
+
+type memoryQueue struct {
+	mu       sync.Mutex
+	timeout  time.Duration
+	count    int
+	pending  []Delivery
+	inflight map[string]inflightDelivery
+}
+
+func (q *memoryQueue) Send(d Delivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending = append(q.pending, d)
+	return nil
+}
+
+func (q *memoryQueue) Receive() ([]Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	expired := []inflightDelivery{}
+	for receipt, m := range q.inflight {
+		if now.Sub(m.received) >= q.timeout {
+			expired = append(expired, m)
+			delete(q.inflight, receipt)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].count < expired[j].count
+	})
+
+	deliveries := []Delivery{}
+	for _, m := range expired {
+		deliveries = append(deliveries, m.delivery)
+	}
+	deliveries = append(deliveries, q.pending...)
+	q.pending = nil
+
+	output := []Message{}
+	for _, d := range deliveries {
+		q.count++
+		receipt := strconv.Itoa(q.count)
+		q.inflight[receipt] = inflightDelivery{
+			delivery: d,
+			count:    q.count,
+			received: now,
+		}
+		output = append(output, Message{
+			Delivery: d,
+			receipt:  receipt,
+		})
+	}
+
+	return output, nil
+}
+
+func (q *memoryQueue) Delete(m Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.inflight[m.receipt]; !ok {
+		return fmt.Errorf("message %s not in flight", m.receipt)
+	}
+	delete(q.inflight, m.receipt)
+	return nil
+}
+
+// inflightExt marks queue files which have been received but not deleted
+const inflightExt = ".inflight"
+
+// NewFileQueue creates a Queue storing each delivery as a JSON file in the directory
+func NewFileQueue(dir string) (Queue, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.New("error creating queue directory: " + err.Error())
+	}
+
+	return &fileQueue{
+		dir:     dir,
+		timeout: visibilityTimeout,
+	}, nil
+}
+
+type fileQueue struct {
+	mu      sync.Mutex
+	dir     string
+	timeout time.Duration
+}
+
+func (q *fileQueue) Send(d Delivery) error {
+	content, err := json.Marshal(d)
+	if err != nil {
+		return errors.New("error encoding delivery: " + err.Error())
+	}
+
+	name := fmt.Sprintf("%020d-%s.json", time.Now().UnixNano(), filepath.Base(d.ID))
+	tmp := filepath.Join(q.dir, "."+name)
+	if err := ioutil.WriteFile(tmp, content, 0644); err != nil {
+		return errors.New("error writing delivery: " + err.Error())
+	}
+
+	if err := os.Rename(tmp, filepath.Join(q.dir, name)); err != nil {
+		return errors.New("error writing delivery: " + err.Error())
+	}
+
+	return nil
+}
+
+func (q *fileQueue) Receive() ([]Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(q.dir, "*.json"))
+	if err != nil {
+		return nil, errors.New("error listing deliveries: " + err.Error())
+	}
+
+	inflight, err := filepath.Glob(filepath.Join(q.dir, "*.json"+inflightExt))
+	if err != nil {
+		return nil, errors.New("error listing deliveries: " + err.Error())
+	}
+
+	now := time.Now()
+	for _, path := range inflight {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if now.Sub(info.ModTime()) >= q.timeout {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	output := []Message{}
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return output, errors.New("error reading delivery: " + err.Error())
+		}
+
+		d := Delivery{}
+		if err := json.Unmarshal(content, &d); err != nil {
+			log.Printf("skipping invalid delivery file %s: %s\n", path, err.Error())
+			continue
+		}
+
+		receipt := strings.TrimSuffix(path, inflightExt) + inflightExt
+		if receipt != path {
+			if err := os.Rename(path, receipt); err != nil {
+				return output, errors.New("error marking delivery in flight: " + err.Error())
+			}
+		}
+
+		// NOTE: The modification time records when the delivery was received
+		if err := os.Chtimes(receipt, now, now); err != nil {
+			return output, errors.New("error marking delivery in flight: " + err.Error())
+		}
+
+		output = append(output, Message{
+			Delivery: d,
+			receipt:  receipt,
+		})
+	}
+
+	return output, nil
+}
+
+func (q *fileQueue) Delete(m Message) error {
+	if err := os.Remove(m.receipt); err != nil {
+		return errors.New("error deleting delivery: " + err.Error())
+	}
+	return nil
+}
+
+type sqsClient interface {
+	SendMessage(input *sqs.SendMessageInput) (*sqs.SendMessageOutput, error)
+	ReceiveMessage(input *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(input *sqs.DeleteMessageInput) (*sqs.DeleteMessageOutput, error)
+}
+
+// NewSQSQueue creates a Queue backed by the SQS queue at the URL
+func NewSQSQueue(url string) Queue {
+	return &sqsQueue{
+		sqs: sqs.New(session.New()),
+		url: url,
+	}
+}
+
+type sqsQueue struct {
+	sqs sqsClient
+	url string
+}
+
+func (q *sqsQueue) Send(d Delivery) error {
+	content, err := json.Marshal(d)
+	if err != nil {
+		return errors.New("error encoding delivery: " + err.Error())
+	}
+
+	if _, err := q.sqs.SendMessage(&sqs.SendMessageInput{
+		QueueUrl:    aws.String(q.url),
+		MessageBody: aws.String(string(content)),
+	}); err != nil {
+		return fmt.Errorf("send message error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (q *sqsQueue) Receive() ([]Message, error) {
+	result, err := q.sqs.ReceiveMessage(&sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(q.url),
+		MaxNumberOfMessages: aws.Int64(10),
+		WaitTimeSeconds:     aws.Int64(10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("receive message error: %s", err.Error())
+	}
+
+	output := []Message{}
+	for _, msg := range result.Messages {
+		d := Delivery{}
+		if err := json.Unmarshal([]byte(aws.StringValue(msg.Body)), &d); err != nil {
+			log.Printf("skipping invalid delivery message %s: %s\n", aws.StringValue(msg.MessageId), err.Error())
+			continue
+		}
+
+		output = append(output, Message{
+			Delivery: d,
+			receipt:  aws.StringValue(msg.ReceiptHandle),
+		})
+	}
+
+	return output, nil
+}
+
+func (q *sqsQueue) Delete(m Message) error {
+	if _, err := q.sqs.DeleteMessage(&sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(q.url),
+		ReceiptHandle: aws.String(m.receipt),
+	}); err != nil {
+		return fmt.Errorf("delete message error: %s", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package frontend
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+func TestNewQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "heupr-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		desc string
+		spec string
+		err  string
+	}{
+		{
+			desc: "no queue configured",
+			spec: "",
+			err:  "no queue configured",
+		},
+		{
+			desc: "unsupported queue",
+			spec: "kafka:holonet",
+			err:  "unsupported queue: kafka:holonet",
+		},
+		{
+			desc: "memory queue",
+			spec: "memory",
+			err:  "",
+		},
+		{
+			desc: "file queue",
+			spec: "file:" + dir,
+			err:  "",
+		},
+		{
+			desc: "sqs queue",
+			spec: "sqs:https://sqs.us-east-1.amazonaws.com/66/heupr",
+			err:  "",
+		},
+	}
+
+	os.Setenv("AWS_REGION", "us-east-1")
+	defer os.Unsetenv("AWS_REGION")
+
+	for _, test := range tests {
+		q, err := NewQueue(test.spec)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("description: %s, error received: %s, expected: %s", test.desc, err.Error(), test.err)
+			}
+			continue
+		}
+
+		if q == nil {
+			t.Errorf("description: %s, no queue returned", test.desc)
+		}
+	}
+}
+
+func testQueue(t *testing.T, desc string, q Queue) {
+	for _, id := range []string{"order-65", "order-66"} {
+		if err := q.Send(Delivery{ID: id, Event: "issues", Body: `{"action":"opened"}`}); err != nil {
+			t.Fatalf("description: %s, error sending delivery: %s", desc, err.Error())
+		}
+	}
+
+	msgs, err := q.Receive()
+	if err != nil {
+		t.Fatalf("description: %s, error receiving deliveries: %s", desc, err.Error())
+	}
+
+	if len(msgs) != 2 || msgs[0].Delivery.ID != "order-65" || msgs[1].Delivery.ID != "order-66" {
+		t.Fatalf("description: %s, incorrect messages received: %+v", desc, msgs)
+	}
+
+	if msgs[1].Delivery.Body != `{"action":"opened"}` {
+		t.Errorf("description: %s, incorrect body received: %s", desc, msgs[1].Delivery.Body)
+	}
+
+	again, err := q.Receive()
+	if err != nil || len(again) != 0 {
+		t.Errorf("description: %s, in flight messages received again: %+v, error: %v", desc, again, err)
+	}
+
+	if err := q.Delete(msgs[0]); err != nil {
+		t.Errorf("description: %s, error deleting message: %s", desc, err.Error())
+	}
+
+	if err := q.Delete(msgs[0]); err == nil {
+		t.Errorf("description: %s, no error deleting message twice", desc)
+	}
+
+	expire(q)
+
+	retried, err := q.Receive()
+	if err != nil || len(retried) != 1 || retried[0].Delivery.ID != "order-66" {
+		t.Fatalf("description: %s, undeleted message not received again: %+v, error: %v", desc, retried, err)
+	}
+
+	if err := q.Delete(retried[0]); err != nil {
+		t.Errorf("description: %s, error deleting retried message: %s", desc, err.Error())
+	}
+
+	if again, err := q.Receive(); err != nil || len(again) != 0 {
+		t.Errorf("description: %s, deleted message received again: %+v, error: %v", desc, again, err)
+	}
+}
+
+// expire makes messages received from the queue visible again at once
+func expire(q Queue) {
+	switch q := q.(type) {
+	case *memoryQueue:
+		q.timeout = 0
+	case *fileQueue:
+		q.timeout = 0
+	}
+}
+
+func TestMemoryQueue(t *testing.T) {
+	testQueue(t, "memory queue", NewMemoryQueue())
+}
+
+func TestFileQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "heupr-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, err := NewFileQueue(dir)
+	if err != nil {
+		t.Fatalf("description: error creating file queue: %s", err.Error())
+	}
+
+	testQueue(t, "file queue", q)
+
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("description: deleted deliveries left in queue directory, received: %v", files)
+	}
+}
+
+type mockSQSClient struct {
+	sendErr        error
+	receiveOutput  *sqs.ReceiveMessageOutput
+	receiveErr     error
+	deleteErr      error
+	sentBody       string
+	deletedReceipt string
+}
+
+func (m *mockSQSClient) SendMessage(input *sqs.SendMessageInput) (*sqs.SendMessageOutput, error) {
+	m.sentBody = aws.StringValue(input.MessageBody)
+	return &sqs.SendMessageOutput{}, m.sendErr
+}
+
+func (m *mockSQSClient) ReceiveMessage(input *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error) {
+	return m.receiveOutput, m.receiveErr
+}
+
+func (m *mockSQSClient) DeleteMessage(input *sqs.DeleteMessageInput) (*sqs.DeleteMessageOutput, error) {
+	m.deletedReceipt = aws.StringValue(input.ReceiptHandle)
+	return &sqs.DeleteMessageOutput{}, m.deleteErr
+}
+
+func TestSQSQueue(t *testing.T) {
+	tests := []struct {
+		desc          string
+		client        *mockSQSClient
+		sendErr       string
+		receiveErr    string
+		deleteErr     string
+		deliveries    int
+		deletedHandle string
+	}{
+		{
+			desc: "error calling queue",
+			client: &mockSQSClient{
+				sendErr:    errors.New("mock send error"),
+				receiveErr: errors.New("mock receive error"),
+				deleteErr:  errors.New("mock delete error"),
+			},
+			sendErr:    "send message error: mock send error",
+			receiveErr: "receive message error: mock receive error",
+		},
+		{
+			desc: "invalid message skipped",
+			client: &mockSQSClient{
+				receiveOutput: &sqs.ReceiveMessageOutput{
+					Messages: []*sqs.Message{
+						{
+							MessageId:     aws.String("invalid"),
+							ReceiptHandle: aws.String("invalid-handle"),
+							Body:          aws.String("{"),
+						},
+						{
+							MessageId:     aws.String("valid"),
+							ReceiptHandle: aws.String("valid-handle"),
+							Body:          aws.String(`{"id":"order-66","event":"issues","body":"{}"}`),
+						},
+					},
+				},
+			},
+			deliveries:    1,
+			deletedHandle: "valid-handle",
+		},
+	}
+
+	for _, test := range tests {
+		q := &sqsQueue{
+			sqs: test.client,
+			url: "https://sqs.us-east-1.amazonaws.com/66/heupr",
+		}
+
+		if err := q.Send(Delivery{ID: "order-66"}); err != nil && err.Error() != test.sendErr {
+			t.Errorf("description: %s, send error received: %s, expected: %s", test.desc, err.Error(), test.sendErr)
+		}
+
+		msgs, err := q.Receive()
+		if err != nil {
+			if err.Error() != test.receiveErr {
+				t.Errorf("description: %s, receive error received: %s, expected: %s", test.desc, err.Error(), test.receiveErr)
+			}
+			continue
+		}
+
+		if len(msgs) != test.deliveries {
+			t.Errorf("description: %s, deliveries received: %d, expected: %d", test.desc, len(msgs), test.deliveries)
+			continue
+		}
+
+		if err := q.Delete(msgs[0]); err != nil {
+			t.Errorf("description: %s, unexpected delete error: %s", test.desc, err.Error())
+		}
+
+		if test.client.deletedReceipt != test.deletedHandle {
+			t.Errorf("description: %s, deleted receipt received: %s, expected: %s", test.desc, test.client.deletedReceipt, test.deletedHandle)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/go-github/v28/github"
+
+	"github.com/heupr/heupr/backend"
+)
+
+// Recording is a saved webhook delivery
+type Recording struct {
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body"`
+}
+
+func (r Recording) body() (string, error) {
+	raw := bytes.TrimSpace(r.Body)
+	if len(raw) == 0 || raw[0] != '"' {
+		return string(raw), nil
+	}
+
+	output := ""
+	if err := json.Unmarshal(raw, &output); err != nil {
+		return "", errors.New("error parsing recording body: " + err.Error())
+	}
+	return output, nil
+}
+
+// Call is a GitHub API request made by a backend during a replay or dry run
+type Call struct {
+	Backend string `json:"backend,omitempty"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Body    string `json:"body,omitempty"`
+}
+
+// newCall describes the request, leaving its body available to be read again
+func newCall(r *http.Request) (Call, error) {
+	body := []byte{}
+	if r.Body != nil {
+		content, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return Call{}, err
+		}
+		r.Body.Close()
+		body = content
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	name, _ := r.Context().Value(backendKey{}).(string)
+
+	return Call{
+		Backend: name,
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Body:    strings.TrimSpace(string(body)),
+	}, nil
+}
+
+// replaySecret signs replayed deliveries so they pass event validation
+const replaySecret = "replay"
+
+// replayDatabase holds the single installation used by a replay
+type replayDatabase struct{}
+
+func (d replayDatabase) Put(input installConfig) error {
+	return nil
+}
+
+func (d replayDatabase) PutInstallation(input installation) error {
+	return nil
+}
+
+func (d replayDatabase) PutRepo(input repository) error {
+	return nil
+}
+
+func (d replayDatabase) Get(key interface{}) (installConfig, error) {
+	output := installConfig{
+		AppID:          1,
+		InstallationID: 1,
+		WebhookSecret:  replaySecret,
+	}
+	if fullName, ok := key.(string); ok {
+		output.FullName = fullName
+	}
+	return output, nil
+}
+
+func (d replayDatabase) PutConfig(input cachedConfig) error {
+	return nil
+}
+
+func (d replayDatabase) GetConfig(path string) (cachedConfig, error) {
+	return cachedConfig{}, errNotCached
+}
+
+func (d replayDatabase) PutDelivery(id string, expires time.Time) (bool, error) {
+	return true, nil
+}
+
+func (d replayDatabase) DeleteDelivery(id string) error {
+	return nil
+}
+
+// replayTransport serves the local config and records every other GitHub request
+type replayTransport struct {
+	config []byte
+	next   http.RoundTripper
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (t *replayTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(r.URL.Path, "/contents/.heupr.yml") {
+		return t.content(r)
+	}
+
+	call, err := newCall(r)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.calls = append(t.calls, call)
+	t.mu.Unlock()
+
+	if t.next != nil && readOnly(r.Method) {
+		return t.next.RoundTrip(r)
+	}
+
+	return jsonResponse(r, http.StatusOK, "null"), nil
+}
+
+// captured returns the requests recorded so far
+func (t *replayTransport) captured() []Call {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Call(nil), t.calls...)
+}
+
+// content answers requests for .heupr.yml files with the local config for the repo
+func (t *replayTransport) content(r *http.Request) (*http.Response, error) {
+	if len(t.config) == 0 || strings.Contains(r.URL.Path, "/.github/contents/") {
+		return jsonResponse(r, http.StatusNotFound, `{"message":"Not Found"}`), nil
+	}
+
+	body, err := json.Marshal(&github.RepositoryContent{
+		Type:     github.String("file"),
+		Encoding: github.String("base64"),
+		Content:  github.String(base64.StdEncoding.EncodeToString(t.config)),
+		SHA:      github.String("replay"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonResponse(r, http.StatusOK, string(body)), nil
+}
+
+// jsonResponse creates a response to the request with the JSON body
+func jsonResponse(r *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header: http.Header{
+			"Content-Type": []string{"application/json"},
+		},
+		Body:    ioutil.NopCloser(strings.NewReader(body)),
+		Request: r,
+	}
+}
+
+// Replay runs a recorded delivery through Event and Process against the local config without making changes
+func Replay(rec Recording, config []byte, transport http.RoundTripper, bknds *backend.Registry) (Result, []Call, error) {
+	body, err := rec.body()
+	if err != nil {
+		return Result{}, nil, err
+	}
+
+	headers := map[string]string{}
+	for key, value := range rec.Headers {
+		headers[key] = value
+	}
+
+	mac := hmac.New(sha1.New, []byte(replaySecret))
+	mac.Write([]byte(body))
+	headers["X-Hub-Signature"] = "sha1=" + hex.EncodeToString(mac.Sum(nil))
+	if header(headers, "X-GitHub-Delivery") == "" {
+		headers["X-GitHub-Delivery"] = "replay"
+	}
+
+	db := replayDatabase{}
+	q := NewMemoryQueue()
+
+	resp, err := Event(events.APIGatewayProxyRequest{
+		Headers: headers,
+		Body:    body,
+	}, db, q, bknds)
+	if err != nil {
+		return Result{}, nil, err
+	}
+
+	msgs, err := q.Receive()
+	if err != nil {
+		return Result{}, nil, err
+	}
+
+	if len(msgs) == 0 {
+		result := Result{}
+		if err := json.Unmarshal([]byte(resp.Body), &result); err != nil {
+			return Result{}, nil, errors.New("error parsing event response: " + err.Error())
+		}
+		result.Status = resp.StatusCode
+		return result, nil, nil
+	}
+
+	rt := &replayTransport{
+		config: config,
+		next:   transport,
+	}
+
+	result := process(context.Background(), msgs[0].Delivery, db, bknds, func(appID, installationID int64, file string) (*github.Client, error) {
+		return github.NewClient(&http.Client{Transport: rt}), nil
+	})
+
+	return result, rt.captured(), nil
+}
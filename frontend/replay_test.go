@@ -0,0 +1,202 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+
+	"github.com/heupr/heupr/backend"
+)
+
+type replayBackend struct {
+	testBackend
+	client *github.Client
+}
+
+func (rb *replayBackend) Configure(ctx context.Context, r backend.Request, c *github.Client) {
+	rb.client = c
+}
+
+func (rb *replayBackend) Act(ctx context.Context, r backend.Request, p backend.Payload) error {
+	_, _, err := rb.client.Issues.AddAssignees(ctx, "test-owner", "test-name", 66, []string{"yoda"})
+	return err
+}
+
+func TestReplay(t *testing.T) {
+	defer saveStubs()()
+
+	tests := []struct {
+		desc    string
+		rec     string
+		config  string
+		status  int
+		message string
+		calls   []Call
+		dryRun  []Call
+	}{
+		{
+			desc:    "unsupported event type",
+			rec:     `{"headers": {"X-GitHub-Event": "test-event"}, "body": {}}`,
+			config:  "backends:\n- name: test-backend\n",
+			status:  202,
+			message: "event type test-event not supported",
+			calls:   nil,
+		},
+		{
+			desc:    "invalid local config",
+			rec:     `{"headers": {"X-GitHub-Event": "issues"}, "body": {"repository": {"full_name": "test-owner/test-name"}}}`,
+			config:  "backends:\n- name: missing-backend\n",
+			status:  422,
+			message: `error parsing repo config file: line 2: unknown backend "missing-backend"`,
+			calls:   nil,
+		},
+		{
+			desc:    "backend requests recorded",
+			rec:     `{"headers": {"X-GitHub-Event": "issues"}, "body": "{\"repository\": {\"full_name\": \"test-owner/test-name\"}}"}`,
+			config:  "backends:\n- name: test-backend\n",
+			status:  200,
+			message: "success",
+			calls: []Call{
+				{
+					Backend: "test-backend",
+					Method:  "POST",
+					Path:    "/repos/test-owner/test-name/issues/66/assignees",
+					Body:    `{"assignees":["yoda"]}`,
+				},
+			},
+		},
+		{
+			desc:    "backend requests recorded by dry run",
+			rec:     `{"headers": {"X-GitHub-Event": "issues"}, "body": {"repository": {"full_name": "test-owner/test-name"}}}`,
+			config:  "dry_run:\n  enabled: true\nbackends:\n- name: test-backend\n",
+			status:  200,
+			message: "success",
+			calls:   nil,
+			dryRun: []Call{
+				{
+					Backend: "test-backend",
+					Method:  "POST",
+					Path:    "/repos/test-owner/test-name/issues/66/assignees",
+					Body:    `{"assignees":["yoda"]}`,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		rec := Recording{}
+		if err := json.Unmarshal([]byte(test.rec), &rec); err != nil {
+			t.Fatalf("description: %s, error parsing recording: %s", test.desc, err.Error())
+		}
+
+		bknds := backend.NewRegistry()
+		bknds.Add("test-backend", func() backend.BackendV2 {
+			return &replayBackend{}
+		})
+
+		result, calls, err := Replay(rec, []byte(test.config), nil, bknds)
+		if err != nil {
+			t.Errorf("description: %s, unexpected error: %s", test.desc, err.Error())
+			continue
+		}
+
+		if result.Status != test.status || result.Message != test.message {
+			t.Errorf("description: %s, result received: %d %s, expected: %d %s", test.desc, result.Status, result.Message, test.status, test.message)
+		}
+
+		if !reflect.DeepEqual(calls, test.calls) {
+			t.Errorf("description: %s, calls received: %+v, expected: %+v", test.desc, calls, test.calls)
+		}
+
+		dryRun := []Call(nil)
+		for _, repo := range result.Repositories {
+			dryRun = append(dryRun, repo.DryRun...)
+		}
+		if !reflect.DeepEqual(dryRun, test.dryRun) {
+			t.Errorf("description: %s, dry run calls received: %+v, expected: %+v", test.desc, dryRun, test.dryRun)
+		}
+	}
+}
+
+type forwardTransport struct {
+	methods []string
+}
+
+func (ft *forwardTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ft.methods = append(ft.methods, r.Method)
+	return jsonResponse(r, http.StatusOK, `{"number":66}`), nil
+}
+
+func Test_replayTransport(t *testing.T) {
+	tests := []struct {
+		desc      string
+		method    string
+		path      string
+		body      string
+		forwarded []string
+		recorded  bool
+	}{
+		{
+			desc:      "read request forwarded",
+			method:    "GET",
+			path:      "/repos/test-owner/test-name/issues/66",
+			body:      `{"number":66}`,
+			forwarded: []string{"GET"},
+			recorded:  true,
+		},
+		{
+			desc:      "write request recorded only",
+			method:    "POST",
+			path:      "/repos/test-owner/test-name/issues/66/comments",
+			body:      "null",
+			forwarded: nil,
+			recorded:  true,
+		},
+		{
+			desc:      "local config served",
+			method:    "GET",
+			path:      "/repos/test-owner/test-name/contents/.heupr.yml",
+			forwarded: nil,
+			recorded:  false,
+		},
+	}
+
+	for _, test := range tests {
+		next := &forwardTransport{}
+		rt := &replayTransport{
+			config: []byte("backends: []\n"),
+			next:   next,
+		}
+
+		req, err := http.NewRequest(test.method, "https://api.github.com"+test.path, strings.NewReader(""))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Errorf("description: %s, unexpected error: %s", test.desc, err.Error())
+			continue
+		}
+
+		body, _ := ioutil.ReadAll(resp.Body)
+		if test.body != "" && string(body) != test.body {
+			t.Errorf("description: %s, body received: %s, expected: %s", test.desc, body, test.body)
+		}
+
+		if !reflect.DeepEqual(next.methods, test.forwarded) {
+			t.Errorf("description: %s, forwarded received: %v, expected: %v", test.desc, next.methods, test.forwarded)
+		}
+
+		calls := rt.captured()
+		if recorded := len(calls) == 1 && calls[0].Method == test.method && calls[0].Path == test.path; recorded != test.recorded {
+			t.Errorf("description: %s, calls received: %+v, expected recorded: %t", test.desc, calls, test.recorded)
+		}
+	}
+}
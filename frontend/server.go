@@ -0,0 +1,97 @@
+package frontend
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"github.com/heupr/heupr/backend"
+)
+
+// maxBodySize is the largest request body accepted, matching GitHub's webhook payload limit
+const maxBodySize = 25 << 20
+
+// NewServer creates a standalone HTTP handler serving the install and event routes
+func NewServer(db Database, q Queue, bknds *backend.Registry) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/install", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		request, err := proxyRequest(r)
+		if err != nil {
+			http.Error(w, "error reading request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, _ := Install(request, db)
+		writeResponse(w, resp)
+	})
+
+	mux.HandleFunc("/event", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		request, err := proxyRequest(r)
+		if err != nil {
+			http.Error(w, "error reading request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, _ := Event(request, db, q, bknds)
+		writeResponse(w, resp)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// proxyRequest converts an incoming HTTP request into the proxy integration format
+func proxyRequest(r *http.Request) (events.APIGatewayProxyRequest, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return events.APIGatewayProxyRequest{}, err
+	}
+	defer r.Body.Close()
+
+	headers := make(map[string]string)
+	for key := range r.Header {
+		headers[key] = r.Header.Get(key)
+	}
+
+	params := make(map[string]string)
+	for key := range r.URL.Query() {
+		params[key] = r.URL.Query().Get(key)
+	}
+
+	return events.APIGatewayProxyRequest{
+		Path:                  r.URL.Path,
+		HTTPMethod:            r.Method,
+		Headers:               headers,
+		QueryStringParameters: params,
+		Body:                  string(body),
+		IsBase64Encoded:       false,
+	}, nil
+}
+
+// writeResponse writes a proxy integration response to the HTTP response writer
+func writeResponse(w http.ResponseWriter, resp events.APIGatewayProxyResponse) {
+	for key, value := range resp.Headers {
+		w.Header().Set(key, value)
+	}
+
+	w.WriteHeader(resp.StatusCode)
+
+	if _, err := w.Write([]byte(resp.Body)); err != nil {
+		log.Printf("error writing response body: %s\n", err.Error())
+	}
+}
@@ -0,0 +1,169 @@
+package frontend
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/go-github/v28/github"
+
+	"github.com/heupr/heupr/backend"
+)
+
+func Test_proxyRequest(t *testing.T) {
+	r := httptest.NewRequest("POST", "/event?code=order-66", strings.NewReader(`{"action":"opened"}`))
+	r.Header.Set("X-GitHub-Event", "issues")
+
+	request, err := proxyRequest(r)
+	if err != nil {
+		t.Fatalf("description: error converting request, error: %s", err.Error())
+	}
+
+	if value := header(request.Headers, "X-GitHub-Event"); value != "issues" {
+		t.Errorf("description: incorrect event header, received: %s, expected: %s", value, "issues")
+	}
+
+	if value := request.QueryStringParameters["code"]; value != "order-66" {
+		t.Errorf("description: incorrect query parameter, received: %s, expected: %s", value, "order-66")
+	}
+
+	if request.Body != `{"action":"opened"}` {
+		t.Errorf("description: incorrect body, received: %s", request.Body)
+	}
+}
+
+func Test_writeResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeResponse(w, events.APIGatewayProxyResponse{
+		StatusCode: 302,
+		Headers: map[string]string{
+			"Location": "https://heupr.github.io/success",
+		},
+		Body: "success",
+	})
+
+	if w.Code != 302 {
+		t.Errorf("description: incorrect status code, received: %d, expected: %d", w.Code, 302)
+	}
+
+	if location := w.Header().Get("Location"); location != "https://heupr.github.io/success" {
+		t.Errorf("description: incorrect location header, received: %s", location)
+	}
+
+	if w.Body.String() != "success" {
+		t.Errorf("description: incorrect body, received: %s, expected: %s", w.Body.String(), "success")
+	}
+}
+
+func TestNewServer(t *testing.T) {
+	defer saveStubs()()
+
+	validateEvent = func(secret, signature string, body []byte) error {
+		return nil
+	}
+
+	newClient = func(appID, installationID int64, file string) (*github.Client, error) {
+		return github.NewClient(nil), nil
+	}
+
+	getContent = func(c *github.Client, owner, repo, path, ref string) (string, string, error) {
+		return "", "", nil
+	}
+
+	tests := []struct {
+		desc     string
+		method   string
+		path     string
+		body     string
+		headers  map[string]string
+		status   int
+		respBody string
+	}{
+		{
+			desc:     "incorrect install method",
+			method:   "POST",
+			path:     "/install",
+			body:     "",
+			headers:  map[string]string{},
+			status:   405,
+			respBody: "method not allowed\n",
+		},
+		{
+			desc:     "no install code received",
+			method:   "GET",
+			path:     "/install",
+			body:     "",
+			headers:  map[string]string{},
+			status:   400,
+			respBody: errorBody(codeBadRequest, "no code received"),
+		},
+		{
+			desc:     "incorrect event method",
+			method:   "GET",
+			path:     "/event",
+			body:     "",
+			headers:  map[string]string{},
+			status:   405,
+			respBody: "method not allowed\n",
+		},
+		{
+			desc:   "successful event invocation",
+			method: "POST",
+			path:   "/event",
+			body:   `{"repository": {"full_name": "test-owner/test-name"}}`,
+			headers: map[string]string{
+				"X-GitHub-Event":  "issues",
+				"X-Hub-Signature": "test-signature",
+			},
+			status:   202,
+			respBody: resultBody("", "event queued"),
+		},
+		{
+			desc:   "event body too large",
+			method: "POST",
+			path:   "/event",
+			body:   strings.Repeat(" ", maxBodySize+1),
+			headers: map[string]string{
+				"X-GitHub-Event":  "issues",
+				"X-Hub-Signature": "test-signature",
+			},
+			status:   400,
+			respBody: "error reading request: http: request body too large\n",
+		},
+	}
+
+	server := NewServer(&databaseMock{}, NewMemoryQueue(), testFactories(map[string]backend.BackendV2{"test-backend": &testBackend{}}))
+
+	for _, test := range tests {
+		r := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+		for key, value := range test.headers {
+			r.Header.Set(key, value)
+		}
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+
+		if w.Code != test.status {
+			t.Errorf("description: %s, incorrect status code, received: %d, expected: %d", test.desc, w.Code, test.status)
+		}
+
+		if w.Body.String() != test.respBody {
+			t.Errorf("description: %s, incorrect body, received: %s, expected: %s", test.desc, w.Body.String(), test.respBody)
+		}
+	}
+}
+
+func Test_header(t *testing.T) {
+	headers := map[string]string{
+		"X-Github-Event": "issues",
+	}
+
+	if value := header(headers, "X-GitHub-Event"); value != "issues" {
+		t.Errorf("description: error getting header, received: %s, expected: %s", value, "issues")
+	}
+
+	if value := header(headers, "X-Hub-Signature"); value != "" {
+		t.Errorf("description: error getting missing header, received: %s", value)
+	}
+}
@@ -0,0 +1,105 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"github.com/heupr/heupr/backend"
+)
+
+// handleDelivery processes a queued delivery, returning an error only if it failed before any backend ran
+func handleDelivery(ctx context.Context, d Delivery, db Database, bknds *backend.Registry) error {
+	result := Process(ctx, d, db, bknds)
+	log.Printf("delivery: %s, result code: %d, message: %s\n", d.ID, result.Status, result.Message)
+
+	if result.Status < http.StatusMultipleChoices || result.invoked() {
+		return nil
+	}
+
+	if result.Status >= http.StatusInternalServerError {
+		return fmt.Errorf("delivery %s failed: %s", d.ID, result.Message)
+	}
+
+	if d.ID != "" {
+		if err := db.DeleteDelivery(d.ID); err != nil {
+			log.Printf("error releasing delivery %s: %s\n", d.ID, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Poll processes the deliveries currently available from the queue
+func Poll(q Queue, db Database, bknds *backend.Registry) error {
+	msgs, err := q.Receive()
+	if err != nil {
+		return errors.New("error receiving deliveries: " + err.Error())
+	}
+
+	for _, msg := range msgs {
+		if err := handleDelivery(context.Background(), msg.Delivery, db, bknds); err != nil {
+			log.Println(err.Error())
+			continue
+		}
+
+		if err := q.Delete(msg); err != nil {
+			log.Printf("error deleting delivery %s: %s\n", msg.Delivery.ID, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Work polls the queue at the interval until the stop channel is closed
+func Work(q Queue, db Database, bknds *backend.Registry, interval time.Duration, stop <-chan struct{}) {
+	for {
+		if err := Poll(q, db, bknds); err != nil {
+			log.Println(err.Error())
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// workerMargin is kept back from the function deadline so the worker can report backend results
+const workerMargin = 10 * time.Second
+
+// Worker processes deliveries received by a function subscribed to the SQS queue
+func Worker(ctx context.Context, event events.SQSEvent, db Database, bknds *backend.Registry) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline.Add(-workerMargin))
+		defer cancel()
+	}
+
+	failed := []string{}
+	for _, record := range event.Records {
+		d := Delivery{}
+		if err := json.Unmarshal([]byte(record.Body), &d); err != nil {
+			log.Printf("skipping invalid delivery message %s: %s\n", record.MessageId, err.Error())
+			continue
+		}
+
+		if err := handleDelivery(ctx, d, db, bknds); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "; "))
+	}
+
+	return nil
+}
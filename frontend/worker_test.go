@@ -0,0 +1,255 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/go-github/v28/github"
+
+	"github.com/heupr/heupr/backend"
+)
+
+func testWorker(content string, clientErr error) {
+	newClient = func(appID, installationID int64, file string) (*github.Client, error) {
+		return github.NewClient(nil), clientErr
+	}
+
+	getContent = func(c *github.Client, owner, repo, path, ref string) (string, string, error) {
+		if repo == ".github" {
+			return "", "", errNotFound
+		}
+		return content, "test-sha", nil
+	}
+}
+
+func TestPoll(t *testing.T) {
+	defer saveStubs()()
+
+	tests := []struct {
+		desc      string
+		content   string
+		clientErr error
+		actErr    error
+		inflight  int
+		delivered bool
+	}{
+		{
+			desc:      "successful delivery deleted",
+			content:   "backends:\n- name: test-backend\n",
+			clientErr: nil,
+			actErr:    nil,
+			inflight:  0,
+			delivered: true,
+		},
+		{
+			desc:      "invalid config deleted and delivery released",
+			content:   "backends:\n- name: missing-backend\n",
+			clientErr: nil,
+			actErr:    nil,
+			inflight:  0,
+			delivered: false,
+		},
+		{
+			desc:      "failed backend deleted without retry",
+			content:   "backends:\n- name: test-backend\n",
+			clientErr: nil,
+			actErr:    errors.New("mock act error"),
+			inflight:  0,
+			delivered: true,
+		},
+		{
+			desc:      "failed delivery hidden until retried",
+			content:   "backends:\n- name: test-backend\n",
+			clientErr: errors.New("mock client error"),
+			actErr:    nil,
+			inflight:  1,
+			delivered: true,
+		},
+	}
+
+	for _, test := range tests {
+		testWorker(test.content, test.clientErr)
+
+		q := NewMemoryQueue()
+		db := &databaseMock{
+			deliveries: map[string]bool{"order-66": true},
+		}
+		bknds := testFactories(map[string]backend.BackendV2{
+			"test-backend": &testBackend{actErr: test.actErr},
+		})
+
+		if err := q.Send(Delivery{
+			ID:    "order-66",
+			Event: "issues",
+			Body:  `{"repository": {"full_name": "test-owner/test-name"}}`,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := Poll(q, db, bknds); err != nil {
+			t.Errorf("description: %s, unexpected error: %s", test.desc, err.Error())
+		}
+
+		if inflight := len(q.(*memoryQueue).inflight); inflight != test.inflight {
+			t.Errorf("description: %s, in flight received: %d, expected: %d", test.desc, inflight, test.inflight)
+		}
+
+		if db.deliveries["order-66"] != test.delivered {
+			t.Errorf("description: %s, delivery recorded received: %t, expected: %t", test.desc, db.deliveries["order-66"], test.delivered)
+		}
+	}
+}
+
+func TestPollRetry(t *testing.T) {
+	defer saveStubs()()
+
+	testWorker("backends:\n- name: test-backend\n", errors.New("mock client error"))
+
+	bknds := testFactories(map[string]backend.BackendV2{
+		"test-backend": &testBackend{},
+	})
+
+	q := NewMemoryQueue()
+	db := &databaseMock{}
+
+	if err := q.Send(Delivery{
+		ID:    "order-66",
+		Event: "issues",
+		Body:  `{"repository": {"full_name": "test-owner/test-name"}}`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Poll(q, db, bknds); err != nil {
+		t.Fatalf("description: unexpected error on first poll: %s", err.Error())
+	}
+
+	if err := Poll(q, db, bknds); err != nil {
+		t.Fatalf("description: unexpected error on hidden poll: %s", err.Error())
+	}
+
+	if _, ok := q.(*memoryQueue).inflight["1"]; !ok || len(q.(*memoryQueue).inflight) != 1 {
+		t.Fatalf("description: failed delivery received before visibility timeout, in flight: %+v", q.(*memoryQueue).inflight)
+	}
+
+	expire(q)
+	testWorker("backends:\n- name: test-backend\n", nil)
+
+	if err := Poll(q, db, bknds); err != nil {
+		t.Fatalf("description: unexpected error on retry poll: %s", err.Error())
+	}
+
+	if inflight := len(q.(*memoryQueue).inflight); inflight != 0 {
+		t.Errorf("description: retried delivery not deleted, in flight received: %d, expected: 0", inflight)
+	}
+}
+
+func TestWorker(t *testing.T) {
+	defer saveStubs()()
+
+	tests := []struct {
+		desc      string
+		body      string
+		clientErr error
+		actErr    error
+		err       string
+	}{
+		{
+			desc:      "invalid message skipped",
+			body:      "{",
+			clientErr: nil,
+			actErr:    nil,
+			err:       "",
+		},
+		{
+			desc:      "failed delivery returned for retry",
+			body:      `{"id":"order-66","event":"issues","body":"{\"repository\":{\"full_name\":\"test-owner/test-name\"}}"}`,
+			clientErr: errors.New("mock client error"),
+			actErr:    nil,
+			err:       "delivery order-66 failed: error creating client: mock client error",
+		},
+		{
+			desc:      "failed backend not returned for retry",
+			body:      `{"id":"order-66","event":"issues","body":"{\"repository\":{\"full_name\":\"test-owner/test-name\"}}"}`,
+			clientErr: nil,
+			actErr:    errors.New("mock act error"),
+			err:       "",
+		},
+		{
+			desc:      "successful delivery",
+			body:      `{"id":"order-66","event":"issues","body":"{\"repository\":{\"full_name\":\"test-owner/test-name\"}}"}`,
+			clientErr: nil,
+			actErr:    nil,
+			err:       "",
+		},
+	}
+
+	for _, test := range tests {
+		testWorker("backends:\n- name: test-backend\n", test.clientErr)
+
+		bknds := testFactories(map[string]backend.BackendV2{
+			"test-backend": &testBackend{actErr: test.actErr},
+		})
+
+		event := events.SQSEvent{
+			Records: []events.SQSMessage{
+				{
+					MessageId: "test-message",
+					Body:      test.body,
+				},
+			},
+		}
+
+		err := Worker(context.Background(), event, &databaseMock{}, bknds)
+		if (err == nil && test.err != "") || (err != nil && err.Error() != test.err) {
+			t.Errorf("description: %s, error received: %v, expected: %s", test.desc, err, test.err)
+		}
+	}
+}
+
+// deadlineBackend records the deadline its action is given
+type deadlineBackend struct {
+	testBackend
+	deadline time.Time
+}
+
+func (db *deadlineBackend) Act(ctx context.Context, r backend.Request, p backend.Payload) error {
+	db.deadline, _ = ctx.Deadline()
+	return nil
+}
+
+func TestWorkerDeadline(t *testing.T) {
+	defer saveStubs()()
+
+	testWorker("backends:\n- name: test-backend\n", nil)
+	backendTimeout = time.Hour
+
+	bknd := &deadlineBackend{}
+	bknds := testFactories(map[string]backend.BackendV2{
+		"test-backend": bknd,
+	})
+
+	event := events.SQSEvent{
+		Records: []events.SQSMessage{
+			{
+				MessageId: "test-message",
+				Body:      `{"id":"order-66","event":"issues","body":"{\"repository\":{\"full_name\":\"test-owner/test-name\"}}"}`,
+			},
+		},
+	}
+
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	if err := Worker(ctx, event, &databaseMock{}, bknds); err != nil {
+		t.Fatalf("description: unexpected error: %s", err.Error())
+	}
+
+	if expected := deadline.Add(-workerMargin); !bknd.deadline.Equal(expected) {
+		t.Errorf("description: backend deadline received: %s, expected: %s", bknd.deadline, expected)
+	}
+}
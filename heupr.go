@@ -1,67 +1,156 @@
 package main
 
 import (
-	"io/ioutil"
+	"context"
+	"flag"
 	"log"
 	"net/http"
-	"plugin"
-	"strings"
+	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 
 	"github.com/heupr/heupr/backend"
+	_ "github.com/heupr/heupr/backend/assignissue" // built-in backends
+	_ "github.com/heupr/heupr/backend/estimatepr"
+	_ "github.com/heupr/heupr/backend/projectboard"
 	"github.com/heupr/heupr/frontend"
 )
 
 // HANDLER allows for build-time starter configuration
 var HANDLER string
 
-func starter(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
+var pluginDir = "/opt/"
 
-	db := frontend.NewDatabase()
+// registry is built once per process for handlers that invoke backends
+var registry *backend.Registry
 
-	files, err := ioutil.ReadDir("/opt/")
-	if err != nil {
-		return frontend.APIResponse(http.StatusInternalServerError, "error reading plugin files: "+err.Error())
-	}
+// queueSpec selects the queue events are sent to for processing by the worker
+var queueSpec = os.Getenv("HEUPR_QUEUE")
 
-	bknds := []backend.Backend{} // NOTE: Possibly adjust to be a map[string]backend.Backend for index referencing
-	for _, file := range files {
-		if !strings.Contains(file.Name(), ".so") {
-			continue
-		}
+// queue is created once per process for the event handler
+var queue frontend.Queue
 
-		plug, err := plugin.Open("/opt/" + file.Name())
-		if err != nil {
-			return frontend.APIResponse(http.StatusInternalServerError, "error opening plugin file: "+err.Error())
-		}
+// databaseSpec selects where installations, configs, and deliveries are stored
+var databaseSpec = os.Getenv("HEUPR_DATABASE")
 
-		symBackend, err := plug.Lookup("Backend")
-		if err != nil {
-			return frontend.APIResponse(http.StatusInternalServerError, "error looking up backend plugin: "+err.Error())
-		}
+// database is opened once per process and shared by warm Lambda invocations
+var database frontend.Database
 
-		var bknd backend.Backend
-		bknd, ok := symBackend.(backend.Backend)
-		if !ok {
-			return frontend.APIResponse(http.StatusInternalServerError, "error asserting backend plugin type: "+err.Error())
-		}
+func loadRegistry(dir string) *backend.Registry {
+	r := backend.NewRegistry()
+	if err := r.Load(dir); err != nil {
+		log.Printf("no plugin backends loaded: %s\n", err.Error())
+	}
 
-		bknds = append(bknds, bknd)
+	log.Printf("loaded backends: %v\n", r.Names())
+	for name, err := range r.Failures() {
+		log.Printf("failed backend: %s, error: %s\n", name, err.Error())
 	}
 
+	return r
+}
+
+func starter(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	switch HANDLER {
 	case "INSTALL":
-		return frontend.Install(request, db)
+		return frontend.Install(request, database)
 	case "EVENT":
-		return frontend.Event(request, db, bknds)
+		if registry == nil {
+			return frontend.APIResponse(frontend.Result{Status: http.StatusInternalServerError, Message: "backend registry not available"})
+		}
+		return frontend.Event(request, database, queue, registry)
+	}
+
+	return frontend.APIResponse(frontend.Result{Status: http.StatusInternalServerError, Message: "requested lambda type not available"})
+}
+
+func worker(ctx context.Context, event events.SQSEvent) error {
+	return frontend.Worker(ctx, event, database, registry)
+}
+
+func serve(addr string, interval time.Duration) error {
+	r := loadRegistry(pluginDir)
+	db, err := frontend.NewDatabase(databaseSpec)
+	if err != nil {
+		return err
 	}
 
-	return frontend.APIResponse(http.StatusInternalServerError, "requested lambda type not available")
+	spec := queueSpec
+	if spec == "" {
+		spec = "memory"
+	}
+
+	q, err := frontend.NewQueue(spec)
+	if err != nil {
+		return err
+	}
+
+	go frontend.Work(q, db, r, interval, nil)
+
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      frontend.NewServer(db, q, r),
+		ReadTimeout:  time.Minute,
+		WriteTimeout: time.Minute,
+	}
+
+	log.Printf("serving install and event routes on %s\n", addr)
+	return server.ListenAndServe()
+}
+
+// env returns the named environment variable or the fallback value if unset
+func env(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
 }
 
 func main() {
-	lambda.Start(starter)
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		if err := replay(os.Args[2:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	mode := flag.String("mode", env("HEUPR_MODE", "lambda"), "run mode: lambda or server")
+	addr := flag.String("addr", env("HEUPR_ADDR", ":8080"), "listen address for server mode")
+	flag.StringVar(&pluginDir, "plugins", env("HEUPR_PLUGINS", pluginDir), "directory containing optional backend plugin files")
+	flag.StringVar(&queueSpec, "queue", queueSpec, "event queue: memory, file:<directory>, or sqs:<queue url> (server mode defaults to memory)")
+	flag.StringVar(&databaseSpec, "database", databaseSpec, "storage: dynamodb, memory, or bolt:<file> (defaults to dynamodb)")
+	interval := flag.Duration("poll", time.Second, "interval between queue polls in server mode")
+	flag.Parse()
+
+	switch *mode {
+	case "lambda":
+		db, err := frontend.NewDatabase(databaseSpec)
+		if err != nil {
+			log.Fatalf("error creating database: %s", err.Error())
+		}
+		database = db
+
+		switch HANDLER {
+		case "EVENT":
+			registry = loadRegistry(pluginDir)
+			q, err := frontend.NewQueue(queueSpec)
+			if err != nil {
+				log.Fatalf("error creating queue: %s", err.Error())
+			}
+			queue = q
+		case "WORKER":
+			registry = loadRegistry(pluginDir)
+			lambda.Start(worker)
+			return
+		}
+		lambda.Start(starter)
+	case "server":
+		log.Fatal(serve(*addr, *interval))
+	default:
+		log.Fatalf("unsupported mode: %s", *mode)
+	}
 }
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/heupr/heupr/frontend"
+)
+
+// tokenTransport authenticates GitHub requests with an access token
+type tokenTransport struct {
+	token string
+}
+
+func (t tokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	req := new(http.Request)
+	*req = *r
+	req.Header = make(http.Header, len(r.Header))
+	for key, values := range r.Header {
+		req.Header[key] = append([]string{}, values...)
+	}
+	req.Header.Set("Authorization", "token "+t.token)
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// replay runs a saved delivery through the event handler and prints the outcome
+func replay(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.SetOutput(w)
+	config := fs.String("config", ".heupr.yml", "local .heupr.yml file used for the replayed repository, or empty for the default config")
+	token := fs.String("token", "", "GitHub access token; read requests are sent to GitHub when provided and answered locally otherwise, write requests are never sent")
+	plugins := fs.String("plugins", pluginDir, "directory containing optional backend plugin files")
+	fs.Usage = func() {
+		fmt.Fprintln(w, "usage: heupr replay [flags] <delivery file>")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("delivery file required")
+	}
+
+	content, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return errors.New("error reading delivery file: " + err.Error())
+	}
+
+	rec := frontend.Recording{}
+	if err := json.Unmarshal(content, &rec); err != nil {
+		return errors.New("error parsing delivery file: " + err.Error())
+	}
+
+	cfg := []byte{}
+	if *config != "" {
+		cfg, err = ioutil.ReadFile(*config)
+		if err != nil {
+			return errors.New("error reading config file: " + err.Error())
+		}
+	}
+
+	var transport http.RoundTripper
+	if *token != "" {
+		transport = tokenTransport{token: *token}
+	}
+
+	r := loadRegistry(*plugins)
+	result, calls, err := frontend.Replay(rec, cfg, transport, r)
+	if err != nil {
+		return err
+	}
+
+	printReplay(w, result, calls)
+	return nil
+}
+
+func printReplay(w io.Writer, result frontend.Result, calls []frontend.Call) {
+	fmt.Fprintf(w, "result: %d %s\n", result.Status, result.Message)
+
+	for _, repo := range result.Repositories {
+		fmt.Fprintf(w, "\nrepository: %s\n", repo.Repository)
+		if repo.Skipped != "" {
+			fmt.Fprintf(w, "  skipped: %s\n", repo.Skipped)
+			continue
+		}
+		fmt.Fprintf(w, "  config: %s\n", repo.ConfigSource)
+
+		for _, bknd := range repo.Backends {
+			if bknd.Succeeded {
+				fmt.Fprintf(w, "  %s: succeeded\n", bknd.Backend)
+			} else {
+				fmt.Fprintf(w, "  %s: failed: %s\n", bknd.Backend, bknd.Error)
+			}
+		}
+
+		if len(repo.DryRun) > 0 {
+			fmt.Fprintln(w, "  dry run, not made:")
+			printCalls(w, "    ", repo.DryRun)
+		}
+	}
+
+	if len(calls) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\nrequests:")
+	printCalls(w, "  ", calls)
+}
+
+func printCalls(w io.Writer, indent string, calls []frontend.Call) {
+	for _, call := range calls {
+		name := call.Backend
+		if name == "" {
+			name = "heupr"
+		}
+		fmt.Fprintf(w, "%s[%s] %s %s", indent, name, call.Method, call.Path)
+		if call.Body != "" {
+			fmt.Fprintf(w, " %s", call.Body)
+		}
+		fmt.Fprintln(w)
+	}
+}